@@ -0,0 +1,204 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumClusterwideEnvoyConfigResource{}
+	_ resource.ResourceWithConfigure      = &ciliumClusterwideEnvoyConfigResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumClusterwideEnvoyConfigResource{}
+	_ resource.ResourceWithImportState    = &ciliumClusterwideEnvoyConfigResource{}
+)
+
+// NewCiliumClusterwideEnvoyConfigResource is a helper function to simplify the provider implementation.
+func NewCiliumClusterwideEnvoyConfigResource() resource.Resource {
+	return &ciliumClusterwideEnvoyConfigResource{}
+}
+
+// ciliumClusterwideEnvoyConfigResourceModel maps the resource schema data.
+type ciliumClusterwideEnvoyConfigResourceModel struct {
+	ID       types.String            `tfsdk:"id"`
+	Metadata *clusterObjectMetaModel `tfsdk:"metadata"`
+	Spec     *envoyConfigSpecModel   `tfsdk:"spec"`
+}
+
+// ciliumClusterwideEnvoyConfigResource is the resource implementation.
+type ciliumClusterwideEnvoyConfigResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumClusterwideEnvoyConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumClusterwideEnvoyConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_clusterwide_envoy_config"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumClusterwideEnvoyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a CiliumClusterwideEnvoyConfig, a cluster scoped configuration of the Envoy proxy embedded in Cilium.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the configuration, its name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata": metadataAttribute(false),
+			"spec":     envoyConfigSpecAttribute("defaulting to `default`"),
+		},
+	}
+}
+
+// ValidateConfig checks the Envoy resources against their types.
+func (r *ciliumClusterwideEnvoyConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateEnvoyResources(ctx, req.Config)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumClusterwideEnvoyConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumClusterwideEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, err := plan.Spec.expand()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Clusterwide Envoy Config", err.Error())
+		return
+	}
+	ccec := &ciliumEnvoyConfig{
+		ObjectMeta: plan.Metadata.objectMeta(),
+		Spec:       spec,
+	}
+
+	created, err := r.client.CreateCiliumClusterwideEnvoyConfig(ctx, ccec)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Cilium Clusterwide Envoy Config", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Created CiliumClusterwideEnvoyConfig", map[string]interface{}{"name": created.Name})
+
+	plan.ID = types.StringValue(created.Name)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumClusterwideEnvoyConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumClusterwideEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.ID.ValueString()
+	ccec, err := r.client.GetCiliumClusterwideEnvoyConfig(ctx, name)
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, "CiliumClusterwideEnvoyConfig not found, removing it from the state", map[string]interface{}{"name": name})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Clusterwide Envoy Config", kubernetesErrorDetail(err))
+		return
+	}
+
+	spec, err := flattenEnvoyConfigSpec(ccec.Spec, state.Spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Clusterwide Envoy Config", err.Error())
+		return
+	}
+	state.ID = types.StringValue(ccec.Name)
+	state.Metadata = flattenClusterObjectMeta(ccec.ObjectMeta)
+	state.Spec = spec
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumClusterwideEnvoyConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumClusterwideEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, err := plan.Spec.expand()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Clusterwide Envoy Config", err.Error())
+		return
+	}
+
+	meta := plan.Metadata.objectMeta()
+	updated, err := r.client.UpdateCiliumClusterwideEnvoyConfig(ctx, meta.Name, func(ccec *ciliumEnvoyConfig) {
+		ccec.Labels = meta.Labels
+		ccec.Annotations = meta.Annotations
+		ccec.Spec = spec
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Cilium Clusterwide Envoy Config", kubernetesErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(updated.Name)
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumClusterwideEnvoyConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumClusterwideEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteCiliumClusterwideEnvoyConfig(ctx, state.ID.ValueString())
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Delete Cilium Clusterwide Envoy Config", kubernetesErrorDetail(err))
+	}
+}
+
+// ImportState imports a configuration by its name.
+func (r *ciliumClusterwideEnvoyConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" || strings.Contains(req.ID, "/") {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Clusterwide Envoy configs are cluster scoped and imported by name, got %q.", req.ID),
+		)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
@@ -0,0 +1,102 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCiliumClusterwideEnvoyConfigResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_clusterwide_envoy_config" "test" {
+  metadata = {
+    name = "tf-acc-echo"
+  }
+  spec = {
+    services = [
+      { name = "echo", namespace = "default" },
+    ]
+    resources = [
+      jsonencode({
+        "@type" = "type.googleapis.com/envoy.config.listener.v3.Listener"
+        name    = "tf-acc-echo-listener"
+        filter_chains = [{
+          filters = [{
+            name = "envoy.filters.network.tcp_proxy"
+            typed_config = {
+              "@type"     = "type.googleapis.com/envoy.extensions.filters.network.tcp_proxy.v3.TcpProxy"
+              stat_prefix = "echo"
+              cluster     = "default/echo"
+            }
+          }]
+        }]
+      }),
+    ]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_clusterwide_envoy_config.test", "id", "tf-acc-echo"),
+					resource.TestCheckResourceAttrSet("cilium_clusterwide_envoy_config.test", "metadata.uid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_clusterwide_envoy_config.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imported resources are written as canonical JSON.
+				ImportStateVerifyIgnore: []string{"spec.resources"},
+			},
+		},
+	})
+}
+
+func TestCiliumClusterwideEnvoyConfigResourceImport(t *testing.T) {
+	ctx := context.Background()
+	client := testDynamicClient(t, `
+apiVersion: cilium.io/v2
+kind: CiliumClusterwideEnvoyConfig
+metadata:
+  name: echo
+spec:
+  services:
+  - name: echo
+    namespace: apps
+  resources:
+  - "@type": type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment
+    cluster_name: apps/echo
+`)
+
+	state, diags := testImportResource(t, NewCiliumClusterwideEnvoyConfigResource(), client, "echo")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumClusterwideEnvoyConfigResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "echo" || model.Metadata.UID.IsNull() {
+		t.Errorf("unexpected id %s or metadata %+v", model.ID, model.Metadata)
+	}
+	if len(model.Spec.Services) != 1 || model.Spec.Services[0].Namespace.ValueString() != "apps" || model.Spec.NodeSelector != nil {
+		t.Errorf("unexpected spec %+v", model.Spec)
+	}
+	want := `{"@type":"type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment","clusterName":"apps/echo"}`
+	if len(model.Spec.Resources) != 1 || model.Spec.Resources[0].ValueString() != want {
+		t.Errorf("imported resources %v, want %s", model.Spec.Resources, want)
+	}
+
+	for _, id := range []string{"", "default/echo"} {
+		_, diags = testImportResource(t, NewCiliumClusterwideEnvoyConfigResource(), client, id)
+		if !diags.HasError() || diags[0].Summary() != "Invalid Import ID" {
+			t.Errorf("import of %q: expected an invalid ID error, got %v", id, diags)
+		}
+	}
+}
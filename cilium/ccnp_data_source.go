@@ -2,11 +2,15 @@ package cilium
 
 import (
 	"context"
+	"fmt"
+
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -32,19 +36,34 @@ func (d *ciliumClusterwideNetworkPolicyDataSource) Metadata(_ context.Context, r
 
 // Schema defines the schema for the data source.
 func (d *ciliumClusterwideNetworkPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	policy, err := ciliumNetworkPolicyAttributes()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Data Source Schema", err.Error())
+		return
+	}
+
 	resp.Schema = schema.Schema{
+		Description: "Lists CiliumClusterwideNetworkPolicies.",
 		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the listed policies, which changes whenever a policy is added, removed or modified.",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the names, labels, annotations, rules and status of the listed policies.",
+				Computed:    true,
+			},
+			"label_selector": schema.StringAttribute{
+				Description: "Kubernetes label selector restricting the listed policies, e.g. `app=web,tier!=db`.",
+				Optional:    true,
+			},
 			"ciliumclusterwidenetworkpolicies": schema.ListNestedAttribute{
-				Computed: true,
+				Description: "The matching policies. Rules of host policies set node_selector instead of endpoint_selector.",
+				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"apiversion": schema.StringAttribute{
-							Computed: true,
-						},
-						"kind": schema.StringAttribute{
-							Computed: true,
-						},
-					},
+					// The schema of clusterwide policies only differs from
+					// namespaced ones by the empty metadata.namespace.
+					Attributes: policy,
 				},
 			},
 		},
@@ -53,16 +72,43 @@ func (d *ciliumClusterwideNetworkPolicyDataSource) Schema(_ context.Context, _ d
 
 // Read refreshes the Terraform state with the latest data.
 func (d *ciliumClusterwideNetworkPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	ccnpl, err := d.client.ListCiliumClusterwideNetworkPolicies(ctx, metav1.ListOptions{})
-	assertNoError(err)
+	var state ciliumClusterwideNetworkPoliciesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	tflog.Debug(ctx, "DBG CCNPL", map[string]interface{}{
-		"cnpnl result": ccnpl,
+	ccnpl, err := d.client.ListCiliumClusterwideNetworkPolicies(ctx, metav1.ListOptions{
+		LabelSelector: state.LabelSelector.ValueString(),
 	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumClusterwideNetworkPolicies", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Listed CiliumClusterwideNetworkPolicies", map[string]interface{}{"count": len(ccnpl.Items)})
 
-	tflog.Debug(ctx, "DBG CCNPL items", map[string]interface{}{
-		"ccnpl.Items: ": ccnpl.Items,
-	})
+	state.CiliumClusterwideNetworkPolicies = nil
+	listed := make([]listedObject, 0, len(ccnpl.Items))
+	for i := range ccnpl.Items {
+		ccnp := &ccnpl.Items[i]
+		policy, err := flattenCiliumClusterwideNetworkPolicyModel(ccnp)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Encode CiliumClusterwideNetworkPolicy", err.Error())
+			return
+		}
+		state.CiliumClusterwideNetworkPolicies = append(state.CiliumClusterwideNetworkPolicies, policy)
+		listed = append(listed, newListedObject(ccnp.ObjectMeta, []interface{}{ccnp.Spec, ccnp.Specs}, ccnp.Status))
+	}
+
+	id, contentHash, err := listIdentity(d.client.clusterIdentity(), listed)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Hash CiliumClusterwideNetworkPolicies", err.Error())
+		return
+	}
+	state.ID = types.StringValue(id)
+	state.ContentHash = types.StringValue(contentHash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 // Configure enables provider-level data or clients to be set in the
@@ -72,16 +118,30 @@ func (d *ciliumClusterwideNetworkPolicyDataSource) Configure(_ context.Context,
 	if req.ProviderData == nil {
 		return
 	}
-	d.client = req.ProviderData.(*CiliumClient)
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
 }
 
-// coffeesDataSourceModel maps the data source schema data.
-type ciliumClusterwideNetworkPolicysDataSourceModel struct {
-	ciliumNetworkPolicys []ciliumClusterwideNetworkPolicyModel `tfsdk:"ciliumclusterwidenetworkpolicies"`
+// ciliumClusterwideNetworkPoliciesDataSourceModel maps the data source schema data.
+type ciliumClusterwideNetworkPoliciesDataSourceModel struct {
+	ID                               types.String               `tfsdk:"id"`
+	ContentHash                      types.String               `tfsdk:"content_hash"`
+	LabelSelector                    types.String               `tfsdk:"label_selector"`
+	CiliumClusterwideNetworkPolicies []ciliumNetworkPolicyModel `tfsdk:"ciliumclusterwidenetworkpolicies"`
 }
 
-// ciliumClusterwideNetworkPolicyModel maps ciliumNetworkPolicys schema data.
-type ciliumClusterwideNetworkPolicyModel struct {
-	ApiVersion types.String `tfsdk:"apiVersion"`
-	Kind       types.String `tfsdk:"kind"`
+// flattenCiliumClusterwideNetworkPolicyModel converts a
+// CiliumClusterwideNetworkPolicy into the model shared by the policy data
+// sources.
+func flattenCiliumClusterwideNetworkPolicyModel(ccnp *ciliumv2.CiliumClusterwideNetworkPolicy) (ciliumNetworkPolicyModel, error) {
+	m, err := flattenCiliumNetworkPolicyModel(clusterwideAsNetworkPolicy(ccnp))
+	m.Kind = types.StringValue(ciliumv2.CCNPKindDefinition)
+	return m, err
 }
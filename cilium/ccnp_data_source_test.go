@@ -0,0 +1,96 @@
+package cilium
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func TestCiliumClusterwideNetworkPolicyDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+
+	data, err := os.ReadFile("../examples/resources/ccnp_test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mypod ciliumv2.CiliumClusterwideNetworkPolicy
+	if err := yaml.Unmarshal(data, &mypod); err != nil {
+		t.Fatal(err)
+	}
+
+	var hostRule api.Rule
+	if err := yaml.Unmarshal([]byte("nodeSelector:\n  matchLabels:\n    role: gateway\ningress:\n- fromEntities:\n  - cluster\n"), &hostRule); err != nil {
+		t.Fatal(err)
+	}
+	host := &ciliumv2.CiliumClusterwideNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-firewall", Labels: map[string]string{"kind": "host"}},
+		Specs:      api.Rules{&hostRule},
+		Status: ciliumv2.CiliumNetworkPolicyStatus{Nodes: map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{
+			"gateway-1": {Error: "unable to resolve entity"},
+		}},
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&mypod, host)}
+
+	state, diags := testReadDataSource(t, NewCiliumClusterwideNetworkPolicyDataSource(), client, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumClusterwideNetworkPoliciesDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.CiliumClusterwideNetworkPolicies) != 2 {
+		t.Fatalf("got %d policies, want 2", len(model.CiliumClusterwideNetworkPolicies))
+	}
+
+	state, _ = testReadDataSource(t, NewCiliumClusterwideNetworkPolicyDataSource(), client, map[string]tftypes.Value{
+		"label_selector": tftypes.NewValue(tftypes.String, "kind=host"),
+	})
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.CiliumClusterwideNetworkPolicies) != 1 {
+		t.Fatalf("got %d policies, want 1", len(model.CiliumClusterwideNetworkPolicies))
+	}
+	policy := model.CiliumClusterwideNetworkPolicies[0]
+
+	if policy.Kind.ValueString() != "CiliumClusterwideNetworkPolicy" || policy.Metadata.Name.ValueString() != "host-firewall" {
+		t.Errorf("unexpected policy %s %s", policy.Kind, policy.Metadata.Name)
+	}
+	if policy.Spec != nil || !policy.SpecJSON.IsNull() {
+		t.Errorf("spec = %+v, spec_json = %s, want null", policy.Spec, policy.SpecJSON)
+	}
+	if len(policy.Specs) != 1 || policy.Specs[0].EndpointSelector != nil || policy.Specs[0].NodeSelector.MatchLabels["role"] != "gateway" {
+		t.Errorf("unexpected specs %+v", policy.Specs)
+	}
+	if policy.SpecsJSON.IsNull() {
+		t.Error("specs_json is null")
+	}
+	if got := policy.Status.Nodes["gateway-1"].Error.ValueString(); got != "unable to resolve entity" {
+		t.Errorf("status.nodes.gateway-1.error = %q", got)
+	}
+}
+
+func TestAccCiliumClusterwideNetworkPolicyDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `data "cilium_ciliumClusterwideNetworkPolicies" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.cilium_ciliumClusterwideNetworkPolicies.test", "ciliumclusterwidenetworkpolicies.#"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,128 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ciliumClusterwideNetworkPolicyLookupDataSource{}
+	_ datasource.DataSourceWithConfigure = &ciliumClusterwideNetworkPolicyLookupDataSource{}
+)
+
+// NewCiliumClusterwideNetworkPolicyLookupDataSource is a helper function to simplify the provider implementation.
+func NewCiliumClusterwideNetworkPolicyLookupDataSource() datasource.DataSource {
+	return &ciliumClusterwideNetworkPolicyLookupDataSource{}
+}
+
+// ciliumClusterwideNetworkPolicyLookupDataSource reads a single
+// CiliumClusterwideNetworkPolicy by name.
+type ciliumClusterwideNetworkPolicyLookupDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumClusterwideNetworkPolicyLookupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_clusterwide_network_policy"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumClusterwideNetworkPolicyLookupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes, err := ciliumNetworkPolicyAttributes()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Data Source Schema", err.Error())
+		return
+	}
+	attributes["id"] = schema.StringAttribute{
+		Description: "Identifier of the policy, its name.",
+		Computed:    true,
+	}
+	attributes["name"] = schema.StringAttribute{
+		Description: "Name of the policy.",
+		Required:    true,
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads a CiliumClusterwideNetworkPolicy by name.",
+		Attributes:  attributes,
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumClusterwideNetworkPolicyLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumClusterwideNetworkPolicyLookupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := state.Name.ValueString()
+
+	ccnp, err := d.client.GetCiliumClusterwideNetworkPolicy(ctx, name)
+	if apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"CiliumClusterwideNetworkPolicy Not Found",
+			fmt.Sprintf("No CiliumClusterwideNetworkPolicy named %q exists in the cluster.", name),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read CiliumClusterwideNetworkPolicy", kubernetesErrorDetail(err))
+		return
+	}
+
+	policy, err := flattenCiliumClusterwideNetworkPolicyModel(ccnp)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Encode CiliumClusterwideNetworkPolicy", err.Error())
+		return
+	}
+	state.ID = types.StringValue(ccnp.Name)
+	state.ApiVersion = policy.ApiVersion
+	state.Kind = policy.Kind
+	state.Metadata = policy.Metadata
+	state.Spec = policy.Spec
+	state.Specs = policy.Specs
+	state.SpecJSON = policy.SpecJSON
+	state.SpecsJSON = policy.SpecsJSON
+	state.Status = policy.Status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumClusterwideNetworkPolicyLookupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumClusterwideNetworkPolicyLookupDataSourceModel maps the data source
+// schema data, the attributes of ciliumNetworkPolicyModel plus the lookup key.
+type ciliumClusterwideNetworkPolicyLookupDataSourceModel struct {
+	ID         types.String               `tfsdk:"id"`
+	Name       types.String               `tfsdk:"name"`
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Spec       *ruleModel                 `tfsdk:"spec"`
+	Specs      []ruleModel                `tfsdk:"specs"`
+	SpecJSON   types.String               `tfsdk:"spec_json"`
+	SpecsJSON  types.String               `tfsdk:"specs_json"`
+	Status     *policyStatusModel         `tfsdk:"status"`
+}
@@ -0,0 +1,82 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func TestCiliumClusterwideNetworkPolicyLookupDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&ciliumv2.CiliumClusterwideNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "host-firewall"},
+		Spec: &api.Rule{
+			NodeSelector: api.NewESFromMatchRequirements(map[string]string{labels.GetExtendedKeyFrom("role"): "gateway"}, nil),
+		},
+	})}
+
+	state, diags := testReadDataSource(t, NewCiliumClusterwideNetworkPolicyLookupDataSource(), client, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "host-firewall"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumClusterwideNetworkPolicyLookupDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "host-firewall" || model.Kind.ValueString() != "CiliumClusterwideNetworkPolicy" {
+		t.Errorf("unexpected id %s or kind %s", model.ID, model.Kind)
+	}
+	if model.Spec == nil || model.Spec.NodeSelector.MatchLabels["role"] != "gateway" {
+		t.Errorf("unexpected spec %+v", model.Spec)
+	}
+
+	_, diags = testReadDataSource(t, NewCiliumClusterwideNetworkPolicyLookupDataSource(), client, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "missing"),
+	})
+	if !diags.HasError() || diags[0].Summary() != "CiliumClusterwideNetworkPolicy Not Found" {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestAccCiliumClusterwideNetworkPolicyLookupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_clusterwide_network_policy" "test" {
+  metadata = {
+    name = "tf-acc-lookup"
+  }
+  spec = {
+    endpoint_selector = {
+      match_labels = {
+        app = "web"
+      }
+    }
+  }
+}
+
+data "cilium_clusterwide_network_policy" "test" {
+  name = cilium_clusterwide_network_policy.test.metadata.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cilium_clusterwide_network_policy.test", "id", "tf-acc-lookup"),
+					resource.TestCheckResourceAttr("data.cilium_clusterwide_network_policy.test", "spec.endpoint_selector.match_labels.app", "web"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,190 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumClusterwideNetworkPolicyResource{}
+	_ resource.ResourceWithConfigure      = &ciliumClusterwideNetworkPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumClusterwideNetworkPolicyResource{}
+	_ resource.ResourceWithImportState    = &ciliumClusterwideNetworkPolicyResource{}
+)
+
+// NewCiliumClusterwideNetworkPolicyResource is a helper function to simplify the provider implementation.
+func NewCiliumClusterwideNetworkPolicyResource() resource.Resource {
+	return &ciliumClusterwideNetworkPolicyResource{}
+}
+
+// ciliumClusterwideNetworkPolicyResourceModel maps the resource schema data.
+type ciliumClusterwideNetworkPolicyResourceModel struct {
+	ID                 types.String             `tfsdk:"id"`
+	Metadata           *clusterObjectMetaModel  `tfsdk:"metadata"`
+	Spec               *ruleModel               `tfsdk:"spec"`
+	Specs              []ruleModel              `tfsdk:"specs"`
+	WaitForRealization *waitForRealizationModel `tfsdk:"wait_for_realization"`
+	Status             types.Object             `tfsdk:"status"`
+}
+
+// ciliumClusterwideNetworkPolicyResource is the resource implementation.
+type ciliumClusterwideNetworkPolicyResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumClusterwideNetworkPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumClusterwideNetworkPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_clusterwide_network_policy"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumClusterwideNetworkPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := policyAttributes("Identifier of the policy, its name.")
+	attributes["metadata"] = metadataAttribute(false)
+	resp.Schema = schema.Schema{
+		Description: "Manages a CiliumClusterwideNetworkPolicy, a cluster scoped Cilium policy which can also select nodes as a host firewall.",
+		Attributes:  attributes,
+	}
+}
+
+// ValidateConfig checks that the policy holds at least one rule and that
+// every rule selects either endpoints or nodes.
+func (r *ciliumClusterwideNetworkPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validatePolicyRules(ctx, req.Config, true)...)
+	resp.Diagnostics.Append(validateWaitForRealization(ctx, req.Config)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumClusterwideNetworkPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumClusterwideNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies := clusterwideNetworkPolicyClient(r.client)
+	created := policies.createPolicy(ctx, plan.Metadata.objectMeta(), plan.Spec, plan.Specs, &resp.Diagnostics)
+	if created == nil {
+		return
+	}
+
+	plan.ID = types.StringValue(created.Name)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	status, diags := policyStatusObject(ctx, created.Status)
+	resp.Diagnostics.Append(diags...)
+	plan.Status = status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	policies.waitForRealization(ctx, plan.WaitForRealization, created, &resp.State, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumClusterwideNetworkPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumClusterwideNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// A state without metadata comes from an import.
+	ccnp := clusterwideNetworkPolicyClient(r.client).readPolicy(ctx, "", state.ID.ValueString(), state.Metadata == nil, &resp.Diagnostics)
+	if ccnp == nil {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	refreshed, diags := flattenCiliumClusterwideNetworkPolicy(ctx, ccnp)
+	resp.Diagnostics.Append(diags...)
+	refreshed.WaitForRealization = state.WaitForRealization
+	resp.Diagnostics.Append(resp.State.Set(ctx, refreshed)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumClusterwideNetworkPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumClusterwideNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies := clusterwideNetworkPolicyClient(r.client)
+	updated := policies.updatePolicy(ctx, plan.Metadata.objectMeta(), plan.Spec, plan.Specs, &resp.Diagnostics)
+	if updated == nil {
+		return
+	}
+
+	plan.ID = types.StringValue(updated.Name)
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	status, diags := policyStatusObject(ctx, updated.Status)
+	resp.Diagnostics.Append(diags...)
+	plan.Status = status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	policies.waitForRealization(ctx, plan.WaitForRealization, updated, &resp.State, &resp.Diagnostics)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumClusterwideNetworkPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumClusterwideNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clusterwideNetworkPolicyClient(r.client).deletePolicy(ctx, "", state.ID.ValueString(), &resp.Diagnostics)
+}
+
+// ImportState imports a policy by its name.
+func (r *ciliumClusterwideNetworkPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" || strings.Contains(req.ID, "/") {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Clusterwide policies are cluster scoped and imported by name, got %q.", req.ID),
+		)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// flattenCiliumClusterwideNetworkPolicy converts a
+// CiliumClusterwideNetworkPolicy, read as a CiliumNetworkPolicy, into the
+// resource model.
+func flattenCiliumClusterwideNetworkPolicy(ctx context.Context, ccnp *ciliumv2.CiliumNetworkPolicy) (ciliumClusterwideNetworkPolicyResourceModel, diag.Diagnostics) {
+	m := ciliumClusterwideNetworkPolicyResourceModel{
+		ID:       types.StringValue(ccnp.Name),
+		Metadata: flattenClusterObjectMeta(ccnp.ObjectMeta),
+	}
+	var diags diag.Diagnostics
+	m.Spec, m.Specs, m.Status, diags = flattenPolicy(ctx, ccnp)
+	return m, diags
+}
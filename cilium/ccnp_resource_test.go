@@ -0,0 +1,129 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+func TestAccCiliumClusterwideNetworkPolicyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing, from examples/resources/ccnp_test.yaml
+			{
+				Config: providerConfig + `
+resource "cilium_clusterwide_network_policy" "test" {
+  metadata = {
+    name = "tf-acc-mypod-ingress"
+  }
+  spec = {
+    endpoint_selector = {
+      match_labels = {
+        example                           = "mypod"
+        "k8s:io.kubernetes.pod.namespace" = "netpols"
+      }
+    }
+    ingress = [
+      {
+        from_endpoints = [{}]
+        to_ports = [
+          {
+            ports = [{ port = "53", protocol = "UDP" }]
+          },
+        ]
+      },
+    ]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_clusterwide_network_policy.test", "id", "tf-acc-mypod-ingress"),
+					resource.TestCheckResourceAttrSet("cilium_clusterwide_network_policy.test", "metadata.uid"),
+					resource.TestCheckResourceAttr("cilium_clusterwide_network_policy.test", "spec.ingress.0.to_ports.0.ports.0.protocol", "UDP"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_clusterwide_network_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			// Update to a host policy using the specs form
+			{
+				Config: providerConfig + `
+resource "cilium_clusterwide_network_policy" "test" {
+  metadata = {
+    name = "tf-acc-mypod-ingress"
+  }
+  specs = [
+    {
+      node_selector = {
+        match_labels = {
+          "tf-acc-host-policy" = "true"
+        }
+      }
+      ingress = [
+        {
+          from_entities = ["cluster"]
+        },
+      ]
+    },
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("cilium_clusterwide_network_policy.test", "spec.endpoint_selector"),
+					resource.TestCheckResourceAttr("cilium_clusterwide_network_policy.test", "specs.0.node_selector.match_labels.tf-acc-host-policy", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestCiliumClusterwideNetworkPolicyResourceImport(t *testing.T) {
+	ctx := context.Background()
+
+	data, err := os.ReadFile("../examples/resources/ccnp_test.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ccnp ciliumv2.CiliumClusterwideNetworkPolicy
+	if err := yaml.Unmarshal(data, &ccnp); err != nil {
+		t.Fatal(err)
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&ccnp)}
+
+	state, diags := testImportResource(t, NewCiliumClusterwideNetworkPolicyResource(), client, ccnp.Name)
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	var model ciliumClusterwideNetworkPolicyResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != ccnp.Name || model.Metadata.Name.ValueString() != ccnp.Name {
+		t.Errorf("unexpected id %s or name %s", model.ID, model.Metadata.Name)
+	}
+	spec, specs, err := expandPolicyRules(model.Spec, model.Specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal([]interface{}{spec, specs})
+	want, _ := json.Marshal([]interface{}{ccnp.Spec, ccnp.Specs})
+	if string(got) != string(want) {
+		t.Errorf("imported rules\n%s\nwant\n%s", got, want)
+	}
+
+	_, diags = testImportResource(t, NewCiliumClusterwideNetworkPolicyResource(), client, "default/"+ccnp.Name)
+	if !diags.HasError() || diags[0].Summary() != "Invalid Import ID" {
+		t.Errorf("expected an invalid ID error, got %v", diags)
+	}
+}
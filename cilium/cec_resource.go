@@ -0,0 +1,214 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumEnvoyConfigResource{}
+	_ resource.ResourceWithConfigure      = &ciliumEnvoyConfigResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumEnvoyConfigResource{}
+	_ resource.ResourceWithImportState    = &ciliumEnvoyConfigResource{}
+)
+
+// NewCiliumEnvoyConfigResource is a helper function to simplify the provider implementation.
+func NewCiliumEnvoyConfigResource() resource.Resource {
+	return &ciliumEnvoyConfigResource{}
+}
+
+// ciliumEnvoyConfigResourceModel maps the resource schema data.
+type ciliumEnvoyConfigResourceModel struct {
+	ID       types.String          `tfsdk:"id"`
+	Metadata *objectMetaModel      `tfsdk:"metadata"`
+	Spec     *envoyConfigSpecModel `tfsdk:"spec"`
+}
+
+// ciliumEnvoyConfigResource is the resource implementation.
+type ciliumEnvoyConfigResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumEnvoyConfigResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumEnvoyConfigResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_envoy_config"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumEnvoyConfigResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a CiliumEnvoyConfig, which configures the Envoy proxy embedded in Cilium for L7 traffic management of services of its namespace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the configuration in namespace/name form.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata": metadataAttribute(true),
+			"spec":     envoyConfigSpecAttribute("always the namespace of the configuration"),
+		},
+	}
+}
+
+// ValidateConfig checks the Envoy resources against their types.
+func (r *ciliumEnvoyConfigResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validateEnvoyResources(ctx, req.Config)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumEnvoyConfigResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, err := plan.Spec.expand()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Envoy Config", err.Error())
+		return
+	}
+	cec := &ciliumEnvoyConfig{
+		ObjectMeta: plan.Metadata.objectMeta(),
+		Spec:       spec,
+	}
+
+	created, err := r.client.CreateCiliumEnvoyConfig(ctx, cec)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Cilium Envoy Config", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Created CiliumEnvoyConfig", map[string]interface{}{"namespace": created.Namespace, "name": created.Name})
+
+	plan.ID = types.StringValue(namespacedID(created.Namespace, created.Name))
+	plan.Metadata.Namespace = types.StringValue(created.Namespace)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumEnvoyConfigResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseNamespacedID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Envoy Config ID", err.Error())
+		return
+	}
+
+	cec, err := r.client.GetCiliumEnvoyConfig(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, "CiliumEnvoyConfig not found, removing it from the state", map[string]interface{}{"namespace": namespace, "name": name})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Envoy Config", kubernetesErrorDetail(err))
+		return
+	}
+
+	spec, err := flattenEnvoyConfigSpec(cec.Spec, state.Spec)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Envoy Config", err.Error())
+		return
+	}
+	state.ID = types.StringValue(namespacedID(cec.Namespace, cec.Name))
+	state.Metadata = flattenObjectMeta(cec.ObjectMeta)
+	state.Spec = spec
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumEnvoyConfigResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, err := plan.Spec.expand()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Envoy Config", err.Error())
+		return
+	}
+
+	meta := plan.Metadata.objectMeta()
+	updated, err := r.client.UpdateCiliumEnvoyConfig(ctx, meta.Namespace, meta.Name, func(cec *ciliumEnvoyConfig) {
+		cec.Labels = meta.Labels
+		cec.Annotations = meta.Annotations
+		cec.Spec = spec
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Cilium Envoy Config", kubernetesErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(namespacedID(updated.Namespace, updated.Name))
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumEnvoyConfigResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumEnvoyConfigResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseNamespacedID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Envoy Config ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteCiliumEnvoyConfig(ctx, namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Delete Cilium Envoy Config", kubernetesErrorDetail(err))
+	}
+}
+
+// ImportState imports a configuration by its namespace/name. A bare name
+// imports the configuration from the default namespace.
+func (r *ciliumEnvoyConfigResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	namespace, name, err := parseNamespacedID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), namespacedID(namespace, name))...)
+}
@@ -0,0 +1,180 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAccCiliumEnvoyConfigResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_envoy_config" "test" {
+  metadata = {
+    name = "tf-acc-echo"
+  }
+  spec = {
+    backend_services = [
+      { name = "echo" },
+    ]
+    resources = [
+      jsonencode({
+        "@type"         = "type.googleapis.com/envoy.config.cluster.v3.Cluster"
+        name            = "default/echo"
+        connect_timeout = "5s"
+        type            = "EDS"
+        lb_policy       = "ROUND_ROBIN"
+      }),
+    ]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_envoy_config.test", "id", "default/tf-acc-echo"),
+					resource.TestCheckResourceAttrSet("cilium_envoy_config.test", "metadata.uid"),
+					resource.TestCheckResourceAttr("cilium_envoy_config.test", "spec.resources.#", "1"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_envoy_config.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// Imported resources are written as canonical JSON.
+				ImportStateVerifyIgnore: []string{"spec.resources"},
+			},
+		},
+	})
+}
+
+func TestCiliumEnvoyConfigResource(t *testing.T) {
+	ctx := context.Background()
+	client := testDynamicClient(t, `
+apiVersion: cilium.io/v2
+kind: CiliumEnvoyConfig
+metadata:
+  name: echo
+  namespace: apps
+  labels:
+    team: platform
+spec:
+  services:
+  - name: echo
+    listener: echo-listener
+  backendServices:
+  - name: echo-v2
+    namespace: apps
+    number: ["8080"]
+  nodeSelector:
+    matchLabels:
+      envoy: "true"
+  resources:
+  - "@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+    name: apps/echo-v2
+    type: EDS
+    connect_timeout: 5s
+`)
+
+	state, diags := testImportResource(t, NewCiliumEnvoyConfigResource(), client, "apps/echo")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumEnvoyConfigResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "apps/echo" || model.Metadata.Labels["team"] != "platform" {
+		t.Errorf("unexpected id %s or metadata %+v", model.ID, model.Metadata)
+	}
+	spec := model.Spec
+	if len(spec.Services) != 1 || !spec.Services[0].Namespace.IsNull() || spec.Services[0].Listener.ValueString() != "echo-listener" {
+		t.Errorf("unexpected services %+v", spec.Services)
+	}
+	if len(spec.BackendServices) != 1 || spec.BackendServices[0].Number[0] != "8080" {
+		t.Errorf("unexpected backend services %+v", spec.BackendServices)
+	}
+	if spec.NodeSelector == nil || spec.NodeSelector.MatchLabels["envoy"] != "true" {
+		t.Errorf("unexpected node selector %+v", spec.NodeSelector)
+	}
+	want := `{"@type":"type.googleapis.com/envoy.config.cluster.v3.Cluster","connectTimeout":"5s","name":"apps/echo-v2","type":"EDS"}`
+	if len(spec.Resources) != 1 || spec.Resources[0].ValueString() != want {
+		t.Errorf("imported resources %v, want %s", spec.Resources, want)
+	}
+
+	// Drop the node selector, as an update would.
+	spec.NodeSelector = nil
+	expanded, err := spec.expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := client.UpdateCiliumEnvoyConfig(ctx, "apps", "echo", func(cec *ciliumEnvoyConfig) {
+		cec.Spec = expanded
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := updated.toUnstructured("CiliumEnvoyConfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, found, _ := unstructured.NestedMap(obj.Object, "spec", "nodeSelector"); found {
+		t.Error("nodeSelector should have been removed")
+	}
+	resources, _, _ := unstructured.NestedSlice(obj.Object, "spec", "resources")
+	if len(resources) != 1 || resources[0].(map[string]interface{})["connectTimeout"] != "5s" {
+		t.Errorf("unexpected resources %v", resources)
+	}
+
+	if err := client.DeleteCiliumEnvoyConfig(ctx, "apps", "echo"); err != nil {
+		t.Fatal(err)
+	}
+	state, diags = testImportResource(t, NewCiliumEnvoyConfigResource(), client, "apps/echo")
+	if diags.HasError() || !state.Raw.IsNull() {
+		t.Errorf("expected a deleted configuration to be removed from the state, got %v", diags)
+	}
+}
+
+func TestCiliumEnvoyConfigValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := NewCiliumEnvoyConfigResource().(tfresource.ResourceWithValidateConfig)
+
+	var schemaResp tfresource.SchemaResponse
+	r.Schema(ctx, tfresource.SchemaRequest{}, &schemaResp)
+
+	for name, tc := range map[string]struct {
+		resources []attr.Value
+		wantError bool
+	}{
+		"valid":   {resources: []attr.Value{types.StringValue(testEnvoyCluster)}},
+		"unknown": {resources: []attr.Value{types.StringValue(testEnvoyCluster), types.StringUnknown()}},
+		"invalid": {resources: []attr.Value{types.StringValue(testEnvoyCluster), types.StringValue(`{"@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster", "lb": "ROUND_ROBIN"}`)}, wantError: true},
+	} {
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if diags := state.SetAttribute(ctx, path.Root("spec").AtName("resources"), types.ListValueMust(types.StringType, tc.resources)); diags.HasError() {
+			t.Fatal(diags)
+		}
+
+		var resp tfresource.ValidateConfigResponse
+		r.ValidateConfig(ctx, tfresource.ValidateConfigRequest{Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw}}, &resp)
+		if resp.Diagnostics.HasError() != tc.wantError {
+			t.Errorf("%s: got diagnostics %v, want error %t", name, resp.Diagnostics, tc.wantError)
+		}
+		if tc.wantError {
+			if p, ok := resp.Diagnostics[0].(interface{ Path() path.Path }); !ok || !p.Path().Equal(path.Root("spec").AtName("resources").AtListIndex(1)) {
+				t.Errorf("%s: error should point at the invalid resource, got %v", name, resp.Diagnostics)
+			}
+		}
+	}
+}
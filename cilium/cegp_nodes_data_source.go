@@ -0,0 +1,159 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &egressGatewayNodesDataSource{}
+	_ datasource.DataSourceWithConfigure = &egressGatewayNodesDataSource{}
+)
+
+// NewEgressGatewayNodesDataSource is a helper function to simplify the provider implementation.
+func NewEgressGatewayNodesDataSource() datasource.DataSource {
+	return &egressGatewayNodesDataSource{}
+}
+
+// egressGatewayNodesDataSource lists the CiliumNodes matching the gateway
+// node selector of a CiliumEgressGatewayPolicy.
+type egressGatewayNodesDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *egressGatewayNodesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_egress_gateway_nodes"
+}
+
+// Schema defines the schema for the data source.
+func (d *egressGatewayNodesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the CiliumNodes matching the gateway node selector of a CiliumEgressGatewayPolicy.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the listed nodes, which changes whenever a matching node is added, removed or modified.",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the names, labels, annotations, spec and status of the listed nodes.",
+				Computed:    true,
+			},
+			"policy_name": schema.StringAttribute{
+				Description: "Name of the CiliumEgressGatewayPolicy.",
+				Required:    true,
+			},
+			"gateway_node": schema.StringAttribute{
+				Description: "Name of the node acting as gateway, the first matching node by name, or null when no node matches.",
+				Computed:    true,
+			},
+			"ciliumnodes": schema.ListNestedAttribute{
+				Description: "The matching CiliumNodes, sorted by name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ciliumNodeAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *egressGatewayNodesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state egressGatewayNodesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.PolicyName.ValueString()
+	cegp, err := d.client.GetCiliumEgressGatewayPolicy(ctx, name)
+	if apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("CiliumEgressGatewayPolicy Not Found", fmt.Sprintf("No CiliumEgressGatewayPolicy %q exists in the cluster.", name))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read CiliumEgressGatewayPolicy", kubernetesErrorDetail(err))
+		return
+	}
+
+	state.CiliumNodes = nil
+	state.GatewayNode = types.StringNull()
+	listed := []listedObject{}
+	// A policy without node selector selects no node.
+	if gateway := cegp.Spec.EgressGateway; gateway != nil && gateway.NodeSelector != nil {
+		selector, err := slimv1.LabelSelectorAsSelector(gateway.NodeSelector)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Gateway Node Selector", fmt.Sprintf("Policy %q: %s", name, err))
+			return
+		}
+		cnl, err := d.client.ListCiliumNodes(ctx, metav1.ListOptions{
+			LabelSelector: selector.String(),
+			Limit:         defaultCiliumNodePageSize,
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to List CiliumNodes", kubernetesErrorDetail(err))
+			return
+		}
+		tflog.Debug(ctx, "Listed gateway CiliumNodes", map[string]interface{}{"policy": name, "count": len(cnl.Items)})
+
+		// Cilium picks the first matching node by name as gateway.
+		sort.Slice(cnl.Items, func(i, j int) bool { return cnl.Items[i].Name < cnl.Items[j].Name })
+		for i := range cnl.Items {
+			cn := &cnl.Items[i]
+			state.CiliumNodes = append(state.CiliumNodes, flattenCiliumNodeModel(cn))
+			listed = append(listed, newListedObject(cn.ObjectMeta, cn.Spec, cn.Status))
+		}
+		if len(cnl.Items) > 0 {
+			state.GatewayNode = types.StringValue(cnl.Items[0].Name)
+		}
+	}
+
+	id, contentHash, err := listIdentity(d.client.clusterIdentity(), listed)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Hash CiliumNodes", err.Error())
+		return
+	}
+	state.ID = types.StringValue(id)
+	state.ContentHash = types.StringValue(contentHash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *egressGatewayNodesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// egressGatewayNodesDataSourceModel maps the data source schema data.
+type egressGatewayNodesDataSourceModel struct {
+	ID          types.String      `tfsdk:"id"`
+	ContentHash types.String      `tfsdk:"content_hash"`
+	PolicyName  types.String      `tfsdk:"policy_name"`
+	GatewayNode types.String      `tfsdk:"gateway_node"`
+	CiliumNodes []ciliumNodeModel `tfsdk:"ciliumnodes"`
+}
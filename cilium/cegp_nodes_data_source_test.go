@@ -0,0 +1,77 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+func TestEgressGatewayNodesDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	gateway := map[string]string{"egress-gateway": "true"}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(
+		&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "worker-2", Labels: gateway}},
+		&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: gateway}},
+		&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "worker-0"}},
+		&ciliumv2.CiliumEgressGatewayPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "partners"},
+			Spec: ciliumv2.CiliumEgressGatewayPolicySpec{
+				EgressGateway: &ciliumv2.EgressGateway{
+					NodeSelector: &slimv1.LabelSelector{MatchLabels: gateway},
+				},
+			},
+		},
+		&ciliumv2.CiliumEgressGatewayPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "nowhere"},
+			Spec: ciliumv2.CiliumEgressGatewayPolicySpec{
+				EgressGateway: &ciliumv2.EgressGateway{
+					NodeSelector: &slimv1.LabelSelector{MatchLabels: map[string]string{"zone": "moon"}},
+				},
+			},
+		},
+	)}
+
+	state, diags := testReadDataSource(t, NewEgressGatewayNodesDataSource(), client, map[string]tftypes.Value{
+		"policy_name": tftypes.NewValue(tftypes.String, "partners"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model egressGatewayNodesDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.CiliumNodes) != 2 || model.CiliumNodes[0].Metadata.Name.ValueString() != "worker-1" ||
+		model.CiliumNodes[1].Metadata.Name.ValueString() != "worker-2" {
+		t.Errorf("unexpected nodes %+v", model.CiliumNodes)
+	}
+	if model.GatewayNode.ValueString() != "worker-1" || model.ID.IsNull() || model.ContentHash.IsNull() {
+		t.Errorf("unexpected gateway node %s, id %s or content hash %s", model.GatewayNode, model.ID, model.ContentHash)
+	}
+
+	state, diags = testReadDataSource(t, NewEgressGatewayNodesDataSource(), client, map[string]tftypes.Value{
+		"policy_name": tftypes.NewValue(tftypes.String, "nowhere"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.CiliumNodes) != 0 || !model.GatewayNode.IsNull() {
+		t.Errorf("expected no gateway, got %s and %d nodes", model.GatewayNode, len(model.CiliumNodes))
+	}
+
+	_, diags = testReadDataSource(t, NewEgressGatewayNodesDataSource(), client, map[string]tftypes.Value{
+		"policy_name": tftypes.NewValue(tftypes.String, "missing"),
+	})
+	if !diags.HasError() || diags[0].Summary() != "CiliumEgressGatewayPolicy Not Found" {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
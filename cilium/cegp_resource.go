@@ -0,0 +1,405 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumEgressGatewayPolicyResource{}
+	_ resource.ResourceWithConfigure      = &ciliumEgressGatewayPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumEgressGatewayPolicyResource{}
+	_ resource.ResourceWithImportState    = &ciliumEgressGatewayPolicyResource{}
+)
+
+// NewCiliumEgressGatewayPolicyResource is a helper function to simplify the provider implementation.
+func NewCiliumEgressGatewayPolicyResource() resource.Resource {
+	return &ciliumEgressGatewayPolicyResource{}
+}
+
+// ciliumEgressGatewayPolicyResourceModel maps the resource schema data.
+type ciliumEgressGatewayPolicyResourceModel struct {
+	ID       types.String                  `tfsdk:"id"`
+	Metadata *clusterObjectMetaModel       `tfsdk:"metadata"`
+	Spec     *egressGatewayPolicySpecModel `tfsdk:"spec"`
+}
+
+type egressGatewayPolicySpecModel struct {
+	Selectors        []egressGatewaySelectorModel `tfsdk:"selectors"`
+	DestinationCIDRs []string                     `tfsdk:"destination_cidrs"`
+	ExcludedCIDRs    []string                     `tfsdk:"excluded_cidrs"`
+	EgressGateway    *egressGatewayModel          `tfsdk:"egress_gateway"`
+}
+
+type egressGatewaySelectorModel struct {
+	NamespaceSelector *selectorModel `tfsdk:"namespace_selector"`
+	PodSelector       *selectorModel `tfsdk:"pod_selector"`
+}
+
+type egressGatewayModel struct {
+	NodeSelector *selectorModel `tfsdk:"node_selector"`
+	Interface    types.String   `tfsdk:"interface"`
+	EgressIP     types.String   `tfsdk:"egress_ip"`
+}
+
+// ciliumEgressGatewayPolicyResource is the resource implementation.
+type ciliumEgressGatewayPolicyResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumEgressGatewayPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumEgressGatewayPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_egress_gateway_policy"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumEgressGatewayPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a CiliumEgressGatewayPolicy, which sends traffic of selected pods to external destinations through a gateway node, SNATed with a fixed IP address.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the policy, its name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata": metadataAttribute(false),
+			"spec": schema.SingleNestedAttribute{
+				Description: "The egress gateway policy.",
+				Required:    true,
+				Attributes: map[string]schema.Attribute{
+					"selectors": schema.ListNestedAttribute{
+						Description: "Pods whose egress traffic goes through the gateway. A pod matching any of the selectors is selected.",
+						Required:    true,
+						NestedObject: schema.NestedAttributeObject{
+							Attributes: map[string]schema.Attribute{
+								"namespace_selector": schema.SingleNestedAttribute{
+									Description: "Selects namespaces by their labels. An empty selector selects all namespaces.",
+									Optional:    true,
+									Attributes:  selectorAttributes(),
+								},
+								"pod_selector": schema.SingleNestedAttribute{
+									Description: "Selects pods by their labels. An empty selector selects all pods.",
+									Optional:    true,
+									Attributes:  selectorAttributes(),
+								},
+							},
+						},
+					},
+					"destination_cidrs": schema.ListAttribute{
+						Description: "IPv4 CIDRs of the destinations reached through the gateway.",
+						Required:    true,
+						ElementType: types.StringType,
+					},
+					"excluded_cidrs": schema.ListAttribute{
+						Description: "IPv4 CIDRs, within `destination_cidrs`, of destinations reached directly.",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+					"egress_gateway": schema.SingleNestedAttribute{
+						Description: "The gateway node and the address traffic is SNATed with.",
+						Required:    true,
+						Attributes: map[string]schema.Attribute{
+							"node_selector": schema.SingleNestedAttribute{
+								Description: "Selects the gateway node by its labels. When several nodes match, " +
+									"the first one by name is used.",
+								Required:   true,
+								Attributes: selectorAttributes(),
+							},
+							"interface": schema.StringAttribute{
+								Description: "Network interface of the gateway node whose first IPv4 address traffic is SNATed with. " +
+									"Conflicts with `egress_ip`.",
+								Optional: true,
+							},
+							"egress_ip": schema.StringAttribute{
+								Description: "IP address of the gateway node traffic is SNATed with. Conflicts with `interface`. " +
+									"When neither is set, the first IPv4 address of the interface of the default route is used.",
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that egress_ip and interface are not both set and
+// that the addresses and CIDRs are IPv4 ones.
+func (r *ciliumEgressGatewayPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	spec := path.Root("spec")
+	gateway := spec.AtName("egress_gateway")
+
+	var iface, egressIP types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, gateway.AtName("interface"), &iface)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, gateway.AtName("egress_ip"), &egressIP)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !iface.IsNull() && !iface.IsUnknown() && !egressIP.IsNull() && !egressIP.IsUnknown() {
+		resp.Diagnostics.AddAttributeError(
+			gateway,
+			"Invalid Egress Gateway",
+			"At most one of interface and egress_ip can be set.",
+		)
+	}
+	if !egressIP.IsNull() && !egressIP.IsUnknown() {
+		if ip := net.ParseIP(egressIP.ValueString()); ip == nil || ip.To4() == nil {
+			resp.Diagnostics.AddAttributeError(
+				gateway.AtName("egress_ip"),
+				"Invalid Egress Gateway",
+				fmt.Sprintf("%q is not an IPv4 address.", egressIP.ValueString()),
+			)
+		}
+	}
+
+	for _, name := range []string{"destination_cidrs", "excluded_cidrs"} {
+		resp.Diagnostics.Append(validateIPv4CIDRs(ctx, req.Config, spec.AtName(name))...)
+	}
+}
+
+// validateIPv4CIDRs checks that the known elements of the list at p are
+// IPv4 CIDRs.
+func validateIPv4CIDRs(ctx context.Context, config tfsdk.Config, p path.Path) diag.Diagnostics {
+	var list types.List
+	diags := config.GetAttribute(ctx, p, &list)
+	if diags.HasError() || list.IsNull() || list.IsUnknown() {
+		return diags
+	}
+	for i, element := range list.Elements() {
+		cidr, ok := element.(types.String)
+		if !ok || cidr.IsNull() || cidr.IsUnknown() {
+			continue
+		}
+		if ip, _, err := net.ParseCIDR(cidr.ValueString()); err != nil || ip.To4() == nil {
+			diags.AddAttributeError(
+				p.AtListIndex(i),
+				"Invalid CIDR",
+				fmt.Sprintf("%q is not an IPv4 CIDR.", cidr.ValueString()),
+			)
+		}
+	}
+	return diags
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumEgressGatewayPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumEgressGatewayPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cegp := &ciliumv2.CiliumEgressGatewayPolicy{
+		ObjectMeta: plan.Metadata.objectMeta(),
+		Spec:       plan.Spec.expand(),
+	}
+	created, err := r.client.CreateCiliumEgressGatewayPolicy(ctx, cegp)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Cilium Egress Gateway Policy", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Created CiliumEgressGatewayPolicy", map[string]interface{}{"name": created.Name})
+
+	plan.ID = types.StringValue(created.Name)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumEgressGatewayPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumEgressGatewayPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.ID.ValueString()
+	cegp, err := r.client.GetCiliumEgressGatewayPolicy(ctx, name)
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, "CiliumEgressGatewayPolicy not found, removing it from the state", map[string]interface{}{"name": name})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Egress Gateway Policy", kubernetesErrorDetail(err))
+		return
+	}
+
+	refreshed := flattenCiliumEgressGatewayPolicy(cegp)
+	keepEmptyEgressGatewayMatchLabels(refreshed.Spec, state.Spec)
+	resp.Diagnostics.Append(resp.State.Set(ctx, refreshed)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumEgressGatewayPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumEgressGatewayPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	meta := plan.Metadata.objectMeta()
+	spec := plan.Spec.expand()
+	updated, err := r.client.UpdateCiliumEgressGatewayPolicy(ctx, meta.Name, func(cegp *ciliumv2.CiliumEgressGatewayPolicy) {
+		cegp.Labels = meta.Labels
+		cegp.Annotations = meta.Annotations
+		cegp.Spec = spec
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Cilium Egress Gateway Policy", kubernetesErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(updated.Name)
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumEgressGatewayPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumEgressGatewayPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.client.DeleteCiliumEgressGatewayPolicy(ctx, state.ID.ValueString())
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Delete Cilium Egress Gateway Policy", kubernetesErrorDetail(err))
+	}
+}
+
+// ImportState imports a policy by its name.
+func (r *ciliumEgressGatewayPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" || strings.Contains(req.ID, "/") {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("Egress gateway policies are cluster scoped and imported by name, got %q.", req.ID),
+		)
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// expand converts the spec model into a CiliumEgressGatewayPolicy spec.
+func (m *egressGatewayPolicySpecModel) expand() ciliumv2.CiliumEgressGatewayPolicySpec {
+	spec := ciliumv2.CiliumEgressGatewayPolicySpec{
+		EgressGateway: &ciliumv2.EgressGateway{
+			NodeSelector: optionalLabelSelector(m.EgressGateway.NodeSelector),
+			Interface:    m.EgressGateway.Interface.ValueString(),
+			EgressIP:     m.EgressGateway.EgressIP.ValueString(),
+		},
+	}
+	for _, rule := range m.Selectors {
+		spec.Selectors = append(spec.Selectors, ciliumv2.EgressRule{
+			NamespaceSelector: optionalLabelSelector(rule.NamespaceSelector),
+			PodSelector:       optionalLabelSelector(rule.PodSelector),
+		})
+	}
+	for _, cidr := range m.DestinationCIDRs {
+		spec.DestinationCIDRs = append(spec.DestinationCIDRs, ciliumv2.IPv4CIDR(cidr))
+	}
+	for _, cidr := range m.ExcludedCIDRs {
+		spec.ExcludedCIDRs = append(spec.ExcludedCIDRs, ciliumv2.IPv4CIDR(cidr))
+	}
+	return spec
+}
+
+// flattenCiliumEgressGatewayPolicy converts a CiliumEgressGatewayPolicy into
+// the resource model.
+func flattenCiliumEgressGatewayPolicy(cegp *ciliumv2.CiliumEgressGatewayPolicy) ciliumEgressGatewayPolicyResourceModel {
+	spec := &egressGatewayPolicySpecModel{}
+	for _, rule := range cegp.Spec.Selectors {
+		spec.Selectors = append(spec.Selectors, egressGatewaySelectorModel{
+			NamespaceSelector: flattenOptionalLabelSelector(rule.NamespaceSelector),
+			PodSelector:       flattenOptionalLabelSelector(rule.PodSelector),
+		})
+	}
+	for _, cidr := range cegp.Spec.DestinationCIDRs {
+		spec.DestinationCIDRs = append(spec.DestinationCIDRs, string(cidr))
+	}
+	for _, cidr := range cegp.Spec.ExcludedCIDRs {
+		spec.ExcludedCIDRs = append(spec.ExcludedCIDRs, string(cidr))
+	}
+	if gateway := cegp.Spec.EgressGateway; gateway != nil {
+		spec.EgressGateway = &egressGatewayModel{
+			NodeSelector: flattenOptionalLabelSelector(gateway.NodeSelector),
+			Interface:    stringValueOrNull(gateway.Interface),
+			EgressIP:     stringValueOrNull(gateway.EgressIP),
+		}
+	}
+	return ciliumEgressGatewayPolicyResourceModel{
+		ID:       types.StringValue(cegp.Name),
+		Metadata: flattenClusterObjectMeta(cegp.ObjectMeta),
+		Spec:     spec,
+	}
+}
+
+func optionalLabelSelector(m *selectorModel) *slimv1.LabelSelector {
+	if m == nil {
+		return nil
+	}
+	selector := expandLabelSelector(m)
+	return &selector
+}
+
+// keepEmptyEgressGatewayMatchLabels keeps the empty match_labels of the
+// selectors of prior in refreshed, see keepEmptyMatchLabels.
+func keepEmptyEgressGatewayMatchLabels(refreshed, prior *egressGatewayPolicySpecModel) {
+	if prior == nil {
+		return
+	}
+	for i := range refreshed.Selectors {
+		if i < len(prior.Selectors) {
+			keepEmptyMatchLabels(refreshed.Selectors[i].NamespaceSelector, prior.Selectors[i].NamespaceSelector)
+			keepEmptyMatchLabels(refreshed.Selectors[i].PodSelector, prior.Selectors[i].PodSelector)
+		}
+	}
+	if refreshed.EgressGateway != nil && prior.EgressGateway != nil {
+		keepEmptyMatchLabels(refreshed.EgressGateway.NodeSelector, prior.EgressGateway.NodeSelector)
+	}
+}
+
+func flattenOptionalLabelSelector(selector *slimv1.LabelSelector) *selectorModel {
+	if selector == nil {
+		return nil
+	}
+	return flattenLabelSelector(*selector)
+}
@@ -0,0 +1,238 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+func TestAccCiliumEgressGatewayPolicyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_egress_gateway_policy" "test" {
+  metadata = {
+    name = "tf-acc-partners"
+  }
+  spec = {
+    selectors = [
+      {
+        pod_selector = {
+          match_labels = {
+            app = "billing"
+          }
+        }
+      },
+    ]
+    destination_cidrs = ["203.0.113.0/24"]
+    egress_gateway = {
+      node_selector = {
+        match_labels = {
+          egress-gateway = "true"
+        }
+      }
+    }
+  }
+}
+
+data "cilium_egress_gateway_nodes" "test" {
+  policy_name = cilium_egress_gateway_policy.test.metadata.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_egress_gateway_policy.test", "id", "tf-acc-partners"),
+					resource.TestCheckResourceAttrSet("cilium_egress_gateway_policy.test", "metadata.uid"),
+					resource.TestCheckResourceAttrSet("data.cilium_egress_gateway_nodes.test", "id"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_egress_gateway_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCiliumEgressGatewayPolicyResourceImport(t *testing.T) {
+	ctx := context.Background()
+
+	var cegp ciliumv2.CiliumEgressGatewayPolicy
+	if err := yaml.Unmarshal([]byte(`
+metadata:
+  name: partners
+  labels:
+    team: billing
+spec:
+  selectors:
+  - podSelector:
+      matchLabels:
+        app: billing
+  - namespaceSelector:
+      matchExpressions:
+      - key: env
+        operator: In
+        values: [prod]
+  destinationCIDRs:
+  - 203.0.113.0/24
+  excludedCIDRs:
+  - 203.0.113.128/25
+  egressGateway:
+    nodeSelector:
+      matchLabels:
+        egress-gateway: "true"
+    egressIP: 192.0.2.10
+`), &cegp); err != nil {
+		t.Fatal(err)
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&cegp)}
+
+	state, diags := testImportResource(t, NewCiliumEgressGatewayPolicyResource(), client, "partners")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumEgressGatewayPolicyResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "partners" || model.Metadata.Labels["team"] != "billing" {
+		t.Errorf("unexpected id %s or metadata %+v", model.ID, model.Metadata)
+	}
+	if model.Spec.Selectors[0].NamespaceSelector != nil || !model.Spec.EgressGateway.Interface.IsNull() {
+		t.Errorf("unset fields should be null, got %+v", model.Spec)
+	}
+	got, _ := json.Marshal(model.Spec.expand())
+	want, _ := json.Marshal(cegp.Spec)
+	if string(got) != string(want) {
+		t.Errorf("imported spec\n%s\nwant\n%s", got, want)
+	}
+
+	for _, id := range []string{"", "default/partners"} {
+		_, diags = testImportResource(t, NewCiliumEgressGatewayPolicyResource(), client, id)
+		if !diags.HasError() || diags[0].Summary() != "Invalid Import ID" {
+			t.Errorf("import of %q: expected an invalid ID error, got %v", id, diags)
+		}
+	}
+}
+
+func TestCiliumEgressGatewayPolicyValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := NewCiliumEgressGatewayPolicyResource().(tfresource.ResourceWithValidateConfig)
+
+	var schemaResp tfresource.SchemaResponse
+	r.Schema(ctx, tfresource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	specType := objectType.AttributeTypes["spec"].(tftypes.Object)
+	gatewayType := specType.AttributeTypes["egress_gateway"].(tftypes.Object)
+
+	cidrs := func(values ...string) tftypes.Value {
+		var elements []tftypes.Value
+		for _, value := range values {
+			elements = append(elements, tftypes.NewValue(tftypes.String, value))
+		}
+		return tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, elements)
+	}
+	str := func(value interface{}) tftypes.Value {
+		return tftypes.NewValue(tftypes.String, value)
+	}
+
+	for name, tc := range map[string]struct {
+		destinationCIDRs tftypes.Value
+		excludedCIDRs    tftypes.Value
+		iface, egressIP  tftypes.Value
+		wantError        bool
+	}{
+		"egress ip": {destinationCIDRs: cidrs("203.0.113.0/24"), excludedCIDRs: cidrs("203.0.113.128/25"), iface: str(nil), egressIP: str("192.0.2.10")},
+		"interface": {destinationCIDRs: cidrs("0.0.0.0/0"), excludedCIDRs: cidrs(), iface: str("eth1"), egressIP: str(nil)},
+		"unknown":   {destinationCIDRs: cidrs("0.0.0.0/0"), excludedCIDRs: cidrs(), iface: str(tftypes.UnknownValue), egressIP: str(tftypes.UnknownValue)},
+		"both":      {destinationCIDRs: cidrs("0.0.0.0/0"), excludedCIDRs: cidrs(), iface: str("eth1"), egressIP: str("192.0.2.10"), wantError: true},
+		"bad ip":    {destinationCIDRs: cidrs("0.0.0.0/0"), excludedCIDRs: cidrs(), iface: str(nil), egressIP: str("192.0.2"), wantError: true},
+		"ipv6 ip":   {destinationCIDRs: cidrs("0.0.0.0/0"), excludedCIDRs: cidrs(), iface: str(nil), egressIP: str("2001:db8::1"), wantError: true},
+		"bad cidr":  {destinationCIDRs: cidrs("203.0.113.0/33"), excludedCIDRs: cidrs(), iface: str(nil), egressIP: str(nil), wantError: true},
+		"ipv6 cidr": {destinationCIDRs: cidrs("0.0.0.0/0"), excludedCIDRs: cidrs("2001:db8::/32"), iface: str(nil), egressIP: str(nil), wantError: true},
+	} {
+		gateway := make(map[string]tftypes.Value, len(gatewayType.AttributeTypes))
+		for attribute, attributeType := range gatewayType.AttributeTypes {
+			gateway[attribute] = tftypes.NewValue(attributeType, nil)
+		}
+		gateway["interface"], gateway["egress_ip"] = tc.iface, tc.egressIP
+
+		spec := make(map[string]tftypes.Value, len(specType.AttributeTypes))
+		for attribute, attributeType := range specType.AttributeTypes {
+			spec[attribute] = tftypes.NewValue(attributeType, nil)
+		}
+		spec["destination_cidrs"], spec["excluded_cidrs"] = tc.destinationCIDRs, tc.excludedCIDRs
+		spec["egress_gateway"] = tftypes.NewValue(gatewayType, gateway)
+
+		attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for attribute, attributeType := range objectType.AttributeTypes {
+			attributes[attribute] = tftypes.NewValue(attributeType, nil)
+		}
+		attributes["spec"] = tftypes.NewValue(specType, spec)
+
+		var resp tfresource.ValidateConfigResponse
+		r.ValidateConfig(ctx, tfresource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+		}, &resp)
+		if resp.Diagnostics.HasError() != tc.wantError {
+			t.Errorf("%s: got diagnostics %v, want error %t", name, resp.Diagnostics, tc.wantError)
+		}
+	}
+}
+
+func TestCiliumEgressGatewayPolicyReadKeepsEmptyMatchLabels(t *testing.T) {
+	ctx := context.Background()
+	cegp := &ciliumv2.CiliumEgressGatewayPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "all"},
+		Spec: ciliumv2.CiliumEgressGatewayPolicySpec{
+			Selectors:        []ciliumv2.EgressRule{{PodSelector: &slimv1.LabelSelector{}}},
+			DestinationCIDRs: []ciliumv2.IPv4CIDR{"0.0.0.0/0"},
+			EgressGateway:    &ciliumv2.EgressGateway{NodeSelector: &slimv1.LabelSelector{}, Interface: "eth1"},
+		},
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(cegp)}
+	r := NewCiliumEgressGatewayPolicyResource()
+	state, diags := testImportResource(t, r, client, "all")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// Configure match_labels = {}, which the API omits.
+	podSelector := path.Root("spec").AtName("selectors").AtListIndex(0).AtName("pod_selector").AtName("match_labels")
+	nodeSelector := path.Root("spec").AtName("egress_gateway").AtName("node_selector").AtName("match_labels")
+	for _, p := range []path.Path{podSelector, nodeSelector} {
+		if diags := state.SetAttribute(ctx, p, map[string]string{}); diags.HasError() {
+			t.Fatal(diags)
+		}
+	}
+	resp := tfresource.ReadResponse{State: state}
+	r.Read(ctx, tfresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	for _, p := range []path.Path{podSelector, nodeSelector} {
+		var matchLabels map[string]string
+		if diags := resp.State.GetAttribute(ctx, p, &matchLabels); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if matchLabels == nil || len(matchLabels) != 0 {
+			t.Errorf("%s refreshed as %v, want an empty map", p, matchLabels)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ciliumEndpointsDataSource{}
+	_ datasource.DataSourceWithConfigure = &ciliumEndpointsDataSource{}
+)
+
+// defaultCiliumEndpointPageSize is the number of CiliumEndpoints requested at
+// once when listing endpoints, like defaultCiliumNodePageSize.
+const defaultCiliumEndpointPageSize = 500
+
+// NewCiliumEndpointsDataSource is a helper function to simplify the provider implementation.
+func NewCiliumEndpointsDataSource() datasource.DataSource {
+	return &ciliumEndpointsDataSource{}
+}
+
+// ciliumEndpointsDataSource lists CiliumEndpoints.
+type ciliumEndpointsDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumEndpointsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_endpoints"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumEndpointsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists CiliumEndpoints, the pods managed by Cilium.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the listed endpoints, which changes whenever an endpoint is added, removed or modified.",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the names, labels, annotations and status of the listed endpoints.",
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace to list the endpoints of. Defaults to all namespaces.",
+				Optional:    true,
+			},
+			"label_selector": schema.StringAttribute{
+				Description: "Kubernetes label selector restricting the listed endpoints, e.g. `app=web`. " +
+					"CiliumEndpoints carry the labels of their pod.",
+				Optional: true,
+			},
+			"endpoints": schema.ListNestedAttribute{
+				Description: "The matching CiliumEndpoints.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ciliumEndpointAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// ciliumEndpointAttributes returns the schema of a CiliumEndpoint read by a
+// data source.
+func ciliumEndpointAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"apiversion": schema.StringAttribute{
+			Computed: true,
+		},
+		"kind": schema.StringAttribute{
+			Computed: true,
+		},
+		"metadata": dataSourceMetadataAttribute(),
+		"status": schema.SingleNestedAttribute{
+			Description: "Status of the endpoint as reported by the Cilium agent.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"id": schema.Int64Attribute{
+					Description: "Identifier of the endpoint, local to the agent managing it.",
+					Computed:    true,
+				},
+				"state": schema.StringAttribute{
+					Description: "State of the endpoint, e.g. `ready` or `waiting-for-identity`.",
+					Computed:    true,
+				},
+				"identity": schema.SingleNestedAttribute{
+					Description: "Security identity of the endpoint.",
+					Computed:    true,
+					Attributes: map[string]schema.Attribute{
+						"id": schema.Int64Attribute{
+							Description: "Numeric identity.",
+							Computed:    true,
+						},
+						"labels": schema.ListAttribute{
+							Description: "Security labels of the identity, in `source:key=value` form.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+				"networking": schema.SingleNestedAttribute{
+					Description: "Addressing of the endpoint.",
+					Computed:    true,
+					Attributes: map[string]schema.Attribute{
+						"addressing": schema.ListNestedAttribute{
+							Description: "IP addresses assigned to the endpoint.",
+							Computed:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: addressPairAttributes(),
+							},
+						},
+						"node_ip": schema.StringAttribute{
+							Description: "IP of the node the endpoint runs on.",
+							Computed:    true,
+						},
+					},
+				},
+				"named_ports": schema.ListNestedAttribute{
+					Description: "Named ports of the containers of the endpoint.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"name": schema.StringAttribute{
+								Computed: true,
+							},
+							"port": schema.Int64Attribute{
+								Computed: true,
+							},
+							"protocol": schema.StringAttribute{
+								Computed: true,
+							},
+						},
+					},
+				},
+				"policy": schema.SingleNestedAttribute{
+					Description: "Policy enforcement of the endpoint.",
+					Computed:    true,
+					Attributes: map[string]schema.Attribute{
+						"ingress": endpointPolicyDirectionAttribute("ingress"),
+						"egress":  endpointPolicyDirectionAttribute("egress"),
+					},
+				},
+				"encryption": schema.SingleNestedAttribute{
+					Description: "Encryption of the traffic of the endpoint.",
+					Computed:    true,
+					Attributes: map[string]schema.Attribute{
+						"key": schema.Int64Attribute{
+							Description: "Index of the encryption key, null when encryption is disabled.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func endpointPolicyDirectionAttribute(direction string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: fmt.Sprintf("Enforcement of %s policy.", direction),
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"enforcing": schema.BoolAttribute{
+				Description: "Whether policy is enforced.",
+				Computed:    true,
+			},
+			"state": schema.StringAttribute{
+				Description: "Policy mode, one of `enforcing`, `non-enforcing` and `disabled`.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumEndpointsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumEndpointsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cepl, err := d.client.ListCiliumEndpoints(ctx, state.Namespace.ValueString(), metav1.ListOptions{
+		LabelSelector: state.LabelSelector.ValueString(),
+		Limit:         defaultCiliumEndpointPageSize,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumEndpoints", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Listed CiliumEndpoints", map[string]interface{}{"count": len(cepl.Items)})
+
+	state.Endpoints = nil
+	listed := make([]listedObject, 0, len(cepl.Items))
+	for i := range cepl.Items {
+		cep := &cepl.Items[i]
+		state.Endpoints = append(state.Endpoints, flattenCiliumEndpointModel(cep))
+		listed = append(listed, newListedObject(cep.ObjectMeta, nil, cep.Status))
+	}
+
+	id, contentHash, err := listIdentity(d.client.clusterIdentity(), listed)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Hash CiliumEndpoints", err.Error())
+		return
+	}
+	state.ID = types.StringValue(id)
+	state.ContentHash = types.StringValue(contentHash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumEndpointsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumEndpointsDataSourceModel maps the data source schema data.
+type ciliumEndpointsDataSourceModel struct {
+	ID            types.String          `tfsdk:"id"`
+	ContentHash   types.String          `tfsdk:"content_hash"`
+	Namespace     types.String          `tfsdk:"namespace"`
+	LabelSelector types.String          `tfsdk:"label_selector"`
+	Endpoints     []ciliumEndpointModel `tfsdk:"endpoints"`
+}
+
+// ciliumEndpointModel maps a CiliumEndpoint.
+type ciliumEndpointModel struct {
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Status     *ciliumEndpointStatusModel `tfsdk:"status"`
+}
+
+type ciliumEndpointStatusModel struct {
+	ID         types.Int64                `tfsdk:"id"`
+	State      types.String               `tfsdk:"state"`
+	Identity   *endpointIdentityModel     `tfsdk:"identity"`
+	Networking *endpointNetworkingModel   `tfsdk:"networking"`
+	NamedPorts []namedPortModel           `tfsdk:"named_ports"`
+	Policy     *endpointPolicyModel       `tfsdk:"policy"`
+	Encryption *ciliumNodeEncryptionModel `tfsdk:"encryption"`
+}
+
+type endpointIdentityModel struct {
+	ID     types.Int64 `tfsdk:"id"`
+	Labels []string    `tfsdk:"labels"`
+}
+
+type endpointNetworkingModel struct {
+	Addressing []addressPairModel `tfsdk:"addressing"`
+	NodeIP     types.String       `tfsdk:"node_ip"`
+}
+
+type namedPortModel struct {
+	Name     types.String `tfsdk:"name"`
+	Port     types.Int64  `tfsdk:"port"`
+	Protocol types.String `tfsdk:"protocol"`
+}
+
+type endpointPolicyModel struct {
+	Ingress *endpointPolicyDirectionModel `tfsdk:"ingress"`
+	Egress  *endpointPolicyDirectionModel `tfsdk:"egress"`
+}
+
+type endpointPolicyDirectionModel struct {
+	Enforcing types.Bool   `tfsdk:"enforcing"`
+	State     types.String `tfsdk:"state"`
+}
+
+// flattenCiliumEndpointModel converts a CiliumEndpoint into the data source
+// model. Parts of the status the agent has not reported yet are null.
+func flattenCiliumEndpointModel(cep *ciliumv2.CiliumEndpoint) ciliumEndpointModel {
+	status := &cep.Status
+	m := ciliumEndpointModel{
+		ApiVersion: types.StringValue(ciliumv2.SchemeGroupVersion.String()),
+		Kind:       types.StringValue(ciliumv2.CEPKindDefinition),
+		Metadata:   flattenDataSourceObjectMeta(cep.ObjectMeta),
+		Status: &ciliumEndpointStatusModel{
+			ID:    int64ValueOrNull(int(status.ID)),
+			State: stringValueOrNull(status.State),
+			Encryption: &ciliumNodeEncryptionModel{
+				Key: int64ValueOrNull(status.Encryption.Key),
+			},
+		},
+	}
+	if status.Identity != nil {
+		m.Status.Identity = &endpointIdentityModel{
+			ID:     types.Int64Value(status.Identity.ID),
+			Labels: nilIfEmpty(status.Identity.Labels),
+		}
+	}
+	if status.Networking != nil {
+		m.Status.Networking = &endpointNetworkingModel{
+			NodeIP: stringValueOrNull(status.Networking.NodeIP),
+		}
+		for _, pair := range status.Networking.Addressing {
+			if pair == nil {
+				continue
+			}
+			m.Status.Networking.Addressing = append(m.Status.Networking.Addressing, addressPairModel{
+				IPv4: stringValueOrNull(pair.IPV4),
+				IPv6: stringValueOrNull(pair.IPV6),
+			})
+		}
+	}
+	for _, port := range status.NamedPorts {
+		if port == nil {
+			continue
+		}
+		m.Status.NamedPorts = append(m.Status.NamedPorts, namedPortModel{
+			Name:     types.StringValue(port.Name),
+			Port:     types.Int64Value(int64(port.Port)),
+			Protocol: stringValueOrNull(port.Protocol),
+		})
+	}
+	if status.Policy != nil {
+		m.Status.Policy = &endpointPolicyModel{
+			Ingress: flattenEndpointPolicyDirection(status.Policy.Ingress),
+			Egress:  flattenEndpointPolicyDirection(status.Policy.Egress),
+		}
+	}
+	return m
+}
+
+func flattenEndpointPolicyDirection(direction *ciliumv2.EndpointPolicyDirection) *endpointPolicyDirectionModel {
+	if direction == nil {
+		return nil
+	}
+	return &endpointPolicyDirectionModel{
+		Enforcing: types.BoolValue(direction.Enforcing),
+		State:     stringValueOrNull(string(direction.State)),
+	}
+}
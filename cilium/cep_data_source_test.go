@@ -0,0 +1,122 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cilium/cilium/api/v1/models"
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+// testCiliumEndpoints returns a ready endpoint of namespace web and an
+// endpoint of namespace db still waiting for its identity.
+func testCiliumEndpoints() []*ciliumv2.CiliumEndpoint {
+	return []*ciliumv2.CiliumEndpoint{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "frontend-7d4b9", Namespace: "web", Labels: map[string]string{"app": "frontend"}},
+			Status: ciliumv2.EndpointStatus{
+				ID:    1234,
+				State: "ready",
+				Identity: &ciliumv2.EndpointIdentity{
+					ID:     56789,
+					Labels: []string{"k8s:app=frontend", "k8s:io.kubernetes.pod.namespace=web"},
+				},
+				Networking: &ciliumv2.EndpointNetworking{
+					Addressing: ciliumv2.AddressPairList{{IPV4: "10.0.1.23", IPV6: "fd00::123"}},
+					NodeIP:     "192.168.0.10",
+				},
+				NamedPorts: models.NamedPorts{{Name: "http", Port: 8080, Protocol: "TCP"}},
+				Policy: &ciliumv2.EndpointPolicy{
+					Ingress: &ciliumv2.EndpointPolicyDirection{Enforcing: true, State: "enforcing"},
+					Egress:  &ciliumv2.EndpointPolicyDirection{State: "non-enforcing"},
+				},
+				Encryption: ciliumv2.EncryptionSpec{Key: 3},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "postgres-0", Namespace: "db", Labels: map[string]string{"app": "postgres"}},
+			Status:     ciliumv2.EndpointStatus{ID: 42, State: "waiting-for-identity"},
+		},
+	}
+}
+
+func TestCiliumEndpointsDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	endpoints := testCiliumEndpoints()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(endpoints[0], endpoints[1])}
+
+	state, diags := testReadDataSource(t, NewCiliumEndpointsDataSource(), client, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumEndpointsDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.Endpoints) != 2 || model.ID.IsNull() || model.ContentHash.IsNull() {
+		t.Fatalf("got %d endpoints, id %s and content hash %s", len(model.Endpoints), model.ID, model.ContentHash)
+	}
+	pending := model.Endpoints[0].Status
+	if pending.State.ValueString() != "waiting-for-identity" || pending.Identity != nil || pending.Networking != nil || !pending.Encryption.Key.IsNull() {
+		t.Errorf("unexpected status of a pending endpoint %+v", pending)
+	}
+
+	for _, filter := range []map[string]tftypes.Value{
+		{"namespace": tftypes.NewValue(tftypes.String, "web")},
+		{"label_selector": tftypes.NewValue(tftypes.String, "app=frontend")},
+	} {
+		state, diags = testReadDataSource(t, NewCiliumEndpointsDataSource(), client, filter)
+		if diags.HasError() {
+			t.Fatal(diags)
+		}
+		if diags := state.Get(ctx, &model); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if len(model.Endpoints) != 1 || model.Endpoints[0].Metadata.Name.ValueString() != "frontend-7d4b9" {
+			t.Fatalf("%v: unexpected endpoints %+v", filter, model.Endpoints)
+		}
+	}
+
+	endpoint := model.Endpoints[0]
+	if endpoint.Kind.ValueString() != "CiliumEndpoint" || endpoint.Metadata.Namespace.ValueString() != "web" {
+		t.Errorf("unexpected kind %s or namespace %s", endpoint.Kind, endpoint.Metadata.Namespace)
+	}
+	status := endpoint.Status
+	if status.ID.ValueInt64() != 1234 || status.Identity.ID.ValueInt64() != 56789 || len(status.Identity.Labels) != 2 {
+		t.Errorf("unexpected identity %+v", status.Identity)
+	}
+	if len(status.Networking.Addressing) != 1 || status.Networking.Addressing[0].IPv6.ValueString() != "fd00::123" ||
+		status.Networking.NodeIP.ValueString() != "192.168.0.10" {
+		t.Errorf("unexpected networking %+v", status.Networking)
+	}
+	if len(status.NamedPorts) != 1 || status.NamedPorts[0].Port.ValueInt64() != 8080 {
+		t.Errorf("unexpected named ports %+v", status.NamedPorts)
+	}
+	if !status.Policy.Ingress.Enforcing.ValueBool() || status.Policy.Egress.State.ValueString() != "non-enforcing" {
+		t.Errorf("unexpected policy %+v", status.Policy)
+	}
+	if status.Encryption.Key.ValueInt64() != 3 {
+		t.Errorf("encryption key = %s, want 3", status.Encryption.Key)
+	}
+}
+
+func TestAccCiliumEndpointsDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `data "cilium_endpoints" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.cilium_endpoints.test", "endpoints.#"),
+					resource.TestCheckResourceAttrSet("data.cilium_endpoints.test", "id"),
+				),
+			},
+		},
+	})
+}
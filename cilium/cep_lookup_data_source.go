@@ -0,0 +1,122 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ciliumEndpointLookupDataSource{}
+	_ datasource.DataSourceWithConfigure = &ciliumEndpointLookupDataSource{}
+)
+
+// NewCiliumEndpointLookupDataSource is a helper function to simplify the provider implementation.
+func NewCiliumEndpointLookupDataSource() datasource.DataSource {
+	return &ciliumEndpointLookupDataSource{}
+}
+
+// ciliumEndpointLookupDataSource reads a single CiliumEndpoint by namespace
+// and name.
+type ciliumEndpointLookupDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumEndpointLookupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_endpoint"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumEndpointLookupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := ciliumEndpointAttributes()
+	attributes["id"] = schema.StringAttribute{
+		Description: "Identifier of the endpoint, in the form `namespace/name`.",
+		Computed:    true,
+	}
+	attributes["name"] = schema.StringAttribute{
+		Description: "Name of the endpoint, the name of its pod.",
+		Required:    true,
+	}
+	attributes["namespace"] = schema.StringAttribute{
+		Description: "Namespace of the endpoint. Defaults to `default`.",
+		Optional:    true,
+		Computed:    true,
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads a CiliumEndpoint by namespace and name.",
+		Attributes:  attributes,
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumEndpointLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumEndpointLookupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	namespace, name := state.Namespace.ValueString(), state.Name.ValueString()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	cep, err := d.client.GetCiliumEndpoint(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"CiliumEndpoint Not Found",
+			fmt.Sprintf("No CiliumEndpoint named %q exists in namespace %q.", name, namespace),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read CiliumEndpoint", kubernetesErrorDetail(err))
+		return
+	}
+
+	endpoint := flattenCiliumEndpointModel(cep)
+	state.ID = types.StringValue(namespacedID(cep.Namespace, cep.Name))
+	state.Namespace = types.StringValue(cep.Namespace)
+	state.ApiVersion = endpoint.ApiVersion
+	state.Kind = endpoint.Kind
+	state.Metadata = endpoint.Metadata
+	state.Status = endpoint.Status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumEndpointLookupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumEndpointLookupDataSourceModel maps the data source schema data, the
+// attributes of ciliumEndpointModel plus the lookup key.
+type ciliumEndpointLookupDataSourceModel struct {
+	ID         types.String               `tfsdk:"id"`
+	Name       types.String               `tfsdk:"name"`
+	Namespace  types.String               `tfsdk:"namespace"`
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Status     *ciliumEndpointStatusModel `tfsdk:"status"`
+}
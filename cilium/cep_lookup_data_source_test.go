@@ -0,0 +1,65 @@
+package cilium
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+func TestCiliumEndpointLookupDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	endpoints := testCiliumEndpoints()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(endpoints[0], endpoints[1])}
+
+	state, diags := testReadDataSource(t, NewCiliumEndpointLookupDataSource(), client, map[string]tftypes.Value{
+		"name":      tftypes.NewValue(tftypes.String, "frontend-7d4b9"),
+		"namespace": tftypes.NewValue(tftypes.String, "web"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumEndpointLookupDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "web/frontend-7d4b9" || model.Kind.ValueString() != "CiliumEndpoint" {
+		t.Errorf("unexpected id %s or kind %s", model.ID, model.Kind)
+	}
+	if model.Status.Identity.ID.ValueInt64() != 56789 || model.Status.State.ValueString() != "ready" {
+		t.Errorf("unexpected status %+v", model.Status)
+	}
+
+	_, diags = testReadDataSource(t, NewCiliumEndpointLookupDataSource(), client, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "postgres-0"),
+	})
+	if !diags.HasError() || diags[0].Summary() != "CiliumEndpoint Not Found" || !strings.Contains(diags[0].Detail(), `namespace "default"`) {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestAccCiliumEndpointLookupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+data "cilium_endpoints" "all" {}
+
+data "cilium_endpoint" "test" {
+  name      = data.cilium_endpoints.all.endpoints[0].metadata.name
+  namespace = data.cilium_endpoints.all.endpoints[0].metadata.namespace
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.cilium_endpoint.test", "status.identity.id", "data.cilium_endpoints.all", "endpoints.0.status.identity.id"),
+				),
+			},
+		},
+	})
+}
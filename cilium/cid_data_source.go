@@ -0,0 +1,195 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ciliumIdentitiesDataSource{}
+	_ datasource.DataSourceWithConfigure = &ciliumIdentitiesDataSource{}
+)
+
+// defaultCiliumIdentityPageSize is the number of CiliumIdentities requested
+// at once when listing identities, like defaultCiliumNodePageSize.
+const defaultCiliumIdentityPageSize = 500
+
+// NewCiliumIdentitiesDataSource is a helper function to simplify the provider implementation.
+func NewCiliumIdentitiesDataSource() datasource.DataSource {
+	return &ciliumIdentitiesDataSource{}
+}
+
+// ciliumIdentitiesDataSource lists CiliumIdentities.
+type ciliumIdentitiesDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumIdentitiesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identities"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumIdentitiesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists CiliumIdentities, the numeric security identities allocated to label sets.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the listed identities, which changes whenever an identity is added, removed or modified.",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the names, labels, security labels and usage counts of the listed identities.",
+				Computed:    true,
+			},
+			"label_selector": schema.StringAttribute{
+				Description: "Kubernetes label selector restricting the listed identities, e.g. `io.kubernetes.pod.namespace=web`. " +
+					"Identities carry the Kubernetes labels of their security labels, without the `k8s:` source.",
+				Optional: true,
+			},
+			"identities": schema.ListNestedAttribute{
+				Description: "The matching CiliumIdentities.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: ciliumIdentityAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// ciliumIdentityAttributes returns the schema of a CiliumIdentity read by a
+// data source.
+func ciliumIdentityAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"apiversion": schema.StringAttribute{
+			Computed: true,
+		},
+		"kind": schema.StringAttribute{
+			Computed: true,
+		},
+		"metadata": dataSourceMetadataAttribute(),
+		"numeric_id": schema.Int64Attribute{
+			Description: "Numeric security identity, as shown by Hubble and `cilium identity list`.",
+			Computed:    true,
+		},
+		"security_labels": schema.MapAttribute{
+			Description: "Labels defining the identity, keyed by `source:key`, e.g. `k8s:app`.",
+			Computed:    true,
+			ElementType: types.StringType,
+		},
+		"usage_count": schema.Int64Attribute{
+			Description: "Number of CiliumEndpoints using the identity.",
+			Computed:    true,
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumIdentitiesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumIdentitiesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	cidl, err := d.client.ListCiliumIdentities(ctx, metav1.ListOptions{
+		LabelSelector: state.LabelSelector.ValueString(),
+		Limit:         defaultCiliumIdentityPageSize,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumIdentities", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Listed CiliumIdentities", map[string]interface{}{"count": len(cidl.Items)})
+
+	usage, err := d.client.identityUsage(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumEndpoints", kubernetesErrorDetail(err))
+		return
+	}
+
+	state.Identities = nil
+	listed := make([]listedObject, 0, len(cidl.Items))
+	for i := range cidl.Items {
+		cid := &cidl.Items[i]
+		identity := flattenCiliumIdentityModel(cid, usage)
+		state.Identities = append(state.Identities, identity)
+		listed = append(listed, newListedObject(cid.ObjectMeta, cid.SecurityLabels, identity.UsageCount.ValueInt64()))
+	}
+
+	id, contentHash, err := listIdentity(d.client.clusterIdentity(), listed)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Hash CiliumIdentities", err.Error())
+		return
+	}
+	state.ID = types.StringValue(id)
+	state.ContentHash = types.StringValue(contentHash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumIdentitiesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumIdentitiesDataSourceModel maps the data source schema data.
+type ciliumIdentitiesDataSourceModel struct {
+	ID            types.String          `tfsdk:"id"`
+	ContentHash   types.String          `tfsdk:"content_hash"`
+	LabelSelector types.String          `tfsdk:"label_selector"`
+	Identities    []ciliumIdentityModel `tfsdk:"identities"`
+}
+
+// ciliumIdentityModel maps a CiliumIdentity.
+type ciliumIdentityModel struct {
+	ApiVersion     types.String               `tfsdk:"apiversion"`
+	Kind           types.String               `tfsdk:"kind"`
+	Metadata       *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	NumericID      types.Int64                `tfsdk:"numeric_id"`
+	SecurityLabels map[string]string          `tfsdk:"security_labels"`
+	UsageCount     types.Int64                `tfsdk:"usage_count"`
+}
+
+// flattenCiliumIdentityModel converts a CiliumIdentity into the data source
+// model, counting its users in usage, the number of endpoints per identity.
+func flattenCiliumIdentityModel(cid *ciliumv2.CiliumIdentity, usage map[int64]int64) ciliumIdentityModel {
+	m := ciliumIdentityModel{
+		ApiVersion:     types.StringValue(ciliumv2.SchemeGroupVersion.String()),
+		Kind:           types.StringValue(ciliumv2.CIDKindDefinition),
+		Metadata:       flattenDataSourceObjectMeta(cid.ObjectMeta),
+		NumericID:      types.Int64Null(),
+		SecurityLabels: nilIfEmptyMap(cid.SecurityLabels),
+		UsageCount:     types.Int64Value(0),
+	}
+	// The name of a CiliumIdentity is its numeric identity.
+	if id, err := strconv.ParseInt(cid.Name, 10, 64); err == nil {
+		m.NumericID = types.Int64Value(id)
+		m.UsageCount = types.Int64Value(usage[id])
+	}
+	return m
+}
@@ -0,0 +1,93 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+// testCiliumIdentityObjects returns the identities of the endpoints of
+// testCiliumEndpoints, the endpoints themselves and a second endpoint of the
+// frontend identity.
+func testCiliumIdentityObjects() []runtime.Object {
+	endpoints := testCiliumEndpoints()
+	replica := endpoints[0].DeepCopy()
+	replica.Name = "frontend-x2k8p"
+	return []runtime.Object{
+		&ciliumv2.CiliumIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "56789", Labels: map[string]string{"app": "frontend", "io.kubernetes.pod.namespace": "web"}},
+			SecurityLabels: map[string]string{
+				"k8s:app":                         "frontend",
+				"k8s:io.kubernetes.pod.namespace": "web",
+			},
+		},
+		&ciliumv2.CiliumIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "60001", Labels: map[string]string{"app": "postgres", "io.kubernetes.pod.namespace": "db"}},
+			SecurityLabels: map[string]string{
+				"k8s:app":                         "postgres",
+				"k8s:io.kubernetes.pod.namespace": "db",
+			},
+		},
+		endpoints[0], endpoints[1], replica,
+	}
+}
+
+func TestCiliumIdentitiesDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(testCiliumIdentityObjects()...)}
+
+	state, diags := testReadDataSource(t, NewCiliumIdentitiesDataSource(), client, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumIdentitiesDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.Identities) != 2 || model.ID.IsNull() || model.ContentHash.IsNull() {
+		t.Fatalf("got %d identities, id %s and content hash %s", len(model.Identities), model.ID, model.ContentHash)
+	}
+	frontend, postgres := model.Identities[0], model.Identities[1]
+	if frontend.NumericID.ValueInt64() != 56789 || frontend.SecurityLabels["k8s:app"] != "frontend" || frontend.UsageCount.ValueInt64() != 2 {
+		t.Errorf("unexpected frontend identity %+v", frontend)
+	}
+	if postgres.Kind.ValueString() != "CiliumIdentity" || postgres.UsageCount.ValueInt64() != 0 {
+		t.Errorf("unexpected postgres identity %+v", postgres)
+	}
+
+	state, diags = testReadDataSource(t, NewCiliumIdentitiesDataSource(), client, map[string]tftypes.Value{
+		"label_selector": tftypes.NewValue(tftypes.String, "io.kubernetes.pod.namespace=db"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.Identities) != 1 || model.Identities[0].NumericID.ValueInt64() != 60001 {
+		t.Errorf("unexpected identities %+v", model.Identities)
+	}
+}
+
+func TestAccCiliumIdentitiesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `data "cilium_identities" "test" {}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.cilium_identities.test", "identities.#"),
+					resource.TestCheckResourceAttrSet("data.cilium_identities.test", "id"),
+				),
+			},
+		},
+	})
+}
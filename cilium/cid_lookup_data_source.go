@@ -0,0 +1,202 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource                   = &ciliumIdentityLookupDataSource{}
+	_ datasource.DataSourceWithConfigure      = &ciliumIdentityLookupDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &ciliumIdentityLookupDataSource{}
+)
+
+// NewCiliumIdentityLookupDataSource is a helper function to simplify the provider implementation.
+func NewCiliumIdentityLookupDataSource() datasource.DataSource {
+	return &ciliumIdentityLookupDataSource{}
+}
+
+// ciliumIdentityLookupDataSource reads a single CiliumIdentity by numeric
+// identity or by security labels.
+type ciliumIdentityLookupDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumIdentityLookupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_identity"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumIdentityLookupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := ciliumIdentityAttributes()
+	attributes["id"] = schema.StringAttribute{
+		Description: "Identifier of the identity, its name.",
+		Computed:    true,
+	}
+	attributes["numeric_id"] = schema.Int64Attribute{
+		Description: "Numeric security identity to look up. Conflicts with `security_labels`.",
+		Optional:    true,
+		Computed:    true,
+	}
+	attributes["security_labels"] = schema.MapAttribute{
+		Description: "Exact set of security labels to look up, keyed by `source:key`, e.g. `k8s:app`. " +
+			"Conflicts with `numeric_id`.",
+		Optional:    true,
+		Computed:    true,
+		ElementType: types.StringType,
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads a CiliumIdentity by numeric identity or by its exact set of security labels.",
+		Attributes:  attributes,
+	}
+}
+
+// ValidateConfig checks that exactly one of numeric_id and security_labels
+// is set.
+func (d *ciliumIdentityLookupDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var numericID types.Int64
+	var securityLabels types.Map
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("numeric_id"), &numericID)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("security_labels"), &securityLabels)...)
+	if resp.Diagnostics.HasError() || numericID.IsUnknown() || securityLabels.IsUnknown() {
+		return
+	}
+	if numericID.IsNull() == securityLabels.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("numeric_id"),
+			"Invalid Identity Lookup",
+			"Exactly one of numeric_id and security_labels must be set.",
+		)
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumIdentityLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumIdentityLookupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var cid *ciliumv2.CiliumIdentity
+	if !state.NumericID.IsNull() {
+		name := strconv.FormatInt(state.NumericID.ValueInt64(), 10)
+		var err error
+		cid, err = d.client.GetCiliumIdentity(ctx, name)
+		if apierrors.IsNotFound(err) {
+			resp.Diagnostics.AddError("CiliumIdentity Not Found", fmt.Sprintf("No CiliumIdentity %s exists in the cluster.", name))
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Read CiliumIdentity", kubernetesErrorDetail(err))
+			return
+		}
+	} else {
+		cidl, err := d.client.ListCiliumIdentities(ctx, metav1.ListOptions{Limit: defaultCiliumIdentityPageSize})
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to List CiliumIdentities", kubernetesErrorDetail(err))
+			return
+		}
+		if cid = findCiliumIdentity(cidl.Items, state.SecurityLabels); cid == nil {
+			resp.Diagnostics.AddError(
+				"CiliumIdentity Not Found",
+				fmt.Sprintf("No CiliumIdentity has exactly the security labels %v.", state.SecurityLabels),
+			)
+			return
+		}
+	}
+
+	usage, err := d.client.identityUsage(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumEndpoints", kubernetesErrorDetail(err))
+		return
+	}
+
+	identity := flattenCiliumIdentityModel(cid, usage)
+	state.ID = types.StringValue(cid.Name)
+	state.ApiVersion = identity.ApiVersion
+	state.Kind = identity.Kind
+	state.Metadata = identity.Metadata
+	state.NumericID = identity.NumericID
+	state.SecurityLabels = identity.SecurityLabels
+	state.UsageCount = identity.UsageCount
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumIdentityLookupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumIdentityLookupDataSourceModel maps the data source schema data, the
+// attributes of ciliumIdentityModel plus the computed ID.
+type ciliumIdentityLookupDataSourceModel struct {
+	ID             types.String               `tfsdk:"id"`
+	ApiVersion     types.String               `tfsdk:"apiversion"`
+	Kind           types.String               `tfsdk:"kind"`
+	Metadata       *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	NumericID      types.Int64                `tfsdk:"numeric_id"`
+	SecurityLabels map[string]string          `tfsdk:"security_labels"`
+	UsageCount     types.Int64                `tfsdk:"usage_count"`
+}
+
+// findCiliumIdentity returns the identity of identities whose security
+// labels are exactly labels. Should several identities have been allocated
+// to the same labels, the lowest numeric identity is returned.
+func findCiliumIdentity(identities []ciliumv2.CiliumIdentity, labels map[string]string) *ciliumv2.CiliumIdentity {
+	var found *ciliumv2.CiliumIdentity
+	var foundID int64
+	for i := range identities {
+		cid := &identities[i]
+		if !stringMapsEqual(cid.SecurityLabels, labels) {
+			continue
+		}
+		id, err := strconv.ParseInt(cid.Name, 10, 64)
+		if err != nil {
+			continue
+		}
+		if found == nil || id < foundID {
+			found, foundID = cid, id
+		}
+	}
+	return found
+}
+
+func stringMapsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+func TestCiliumIdentityLookupDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(testCiliumIdentityObjects()...)}
+	labels := func(values map[string]string) tftypes.Value {
+		elements := make(map[string]tftypes.Value, len(values))
+		for k, v := range values {
+			elements[k] = tftypes.NewValue(tftypes.String, v)
+		}
+		return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements)
+	}
+
+	for name, config := range map[string]map[string]tftypes.Value{
+		"numeric id": {"numeric_id": tftypes.NewValue(tftypes.Number, 56789)},
+		"security labels": {"security_labels": labels(map[string]string{
+			"k8s:app":                         "frontend",
+			"k8s:io.kubernetes.pod.namespace": "web",
+		})},
+	} {
+		state, diags := testReadDataSource(t, NewCiliumIdentityLookupDataSource(), client, config)
+		if diags.HasError() {
+			t.Fatalf("%s: %v", name, diags)
+		}
+		var model ciliumIdentityLookupDataSourceModel
+		if diags := state.Get(ctx, &model); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if model.ID.ValueString() != "56789" || model.NumericID.ValueInt64() != 56789 || len(model.SecurityLabels) != 2 || model.UsageCount.ValueInt64() != 2 {
+			t.Errorf("%s: unexpected identity %+v", name, model)
+		}
+	}
+
+	for name, config := range map[string]map[string]tftypes.Value{
+		"unknown numeric id": {"numeric_id": tftypes.NewValue(tftypes.Number, 12345)},
+		"subset of labels":   {"security_labels": labels(map[string]string{"k8s:app": "frontend"})},
+	} {
+		_, diags := testReadDataSource(t, NewCiliumIdentityLookupDataSource(), client, config)
+		if !diags.HasError() || diags[0].Summary() != "CiliumIdentity Not Found" {
+			t.Errorf("%s: unexpected diagnostics %v", name, diags)
+		}
+	}
+}
+
+func TestCiliumIdentityLookupDataSourceValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	d := NewCiliumIdentityLookupDataSource().(datasource.DataSourceWithValidateConfig)
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+	numericID := tftypes.NewValue(tftypes.Number, 56789)
+	securityLabels := tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+		"k8s:app": tftypes.NewValue(tftypes.String, "frontend"),
+	})
+
+	for _, tc := range []struct {
+		values    map[string]tftypes.Value
+		wantError bool
+	}{
+		{values: map[string]tftypes.Value{}, wantError: true},
+		{values: map[string]tftypes.Value{"numeric_id": numericID}},
+		{values: map[string]tftypes.Value{"security_labels": securityLabels}},
+		{values: map[string]tftypes.Value{"numeric_id": numericID, "security_labels": securityLabels}, wantError: true},
+	} {
+		attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, attributeType := range objectType.AttributeTypes {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+		for name, value := range tc.values {
+			attributes[name] = value
+		}
+
+		var resp datasource.ValidateConfigResponse
+		d.ValidateConfig(ctx, datasource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+		}, &resp)
+		if resp.Diagnostics.HasError() != tc.wantError {
+			t.Errorf("%v: got diagnostics %v, want error %t", tc.values, resp.Diagnostics, tc.wantError)
+		}
+	}
+}
+
+func TestAccCiliumIdentityLookupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+data "cilium_identities" "all" {}
+
+data "cilium_identity" "by_id" {
+  numeric_id = data.cilium_identities.all.identities[0].numeric_id
+}
+
+data "cilium_identity" "by_labels" {
+  security_labels = data.cilium_identity.by_id.security_labels
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.cilium_identity.by_labels", "id", "data.cilium_identity.by_id", "id"),
+					resource.TestCheckResourceAttrSet("data.cilium_identity.by_id", "usage_count"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,569 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumLocalRedirectPolicyResource{}
+	_ resource.ResourceWithConfigure      = &ciliumLocalRedirectPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumLocalRedirectPolicyResource{}
+	_ resource.ResourceWithImportState    = &ciliumLocalRedirectPolicyResource{}
+)
+
+// ciliumLocalRedirectPolicy is a CiliumLocalRedirectPolicy with the fields
+// added to its spec by Cilium releases newer than the API types this
+// provider is built with.
+type ciliumLocalRedirectPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   localRedirectPolicySpec                  `json:"spec,omitempty"`
+	Status ciliumv2.CiliumLocalRedirectPolicyStatus `json:"status"`
+}
+
+type localRedirectPolicySpec struct {
+	ciliumv2.CiliumLocalRedirectPolicySpec `json:",inline"`
+
+	// SkipRedirectFromBackend makes traffic sent by the backends to the
+	// frontend skip the redirection.
+	SkipRedirectFromBackend bool `json:"skipRedirectFromBackend,omitempty"`
+}
+
+func (p *ciliumLocalRedirectPolicy) toUnstructured() (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(p)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(ciliumv2.SchemeGroupVersion.WithKind(ciliumv2.CLRPKindDefinition))
+	return u, nil
+}
+
+func localRedirectPolicyFromUnstructured(obj *unstructured.Unstructured) (*ciliumLocalRedirectPolicy, error) {
+	p := &ciliumLocalRedirectPolicy{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// NewCiliumLocalRedirectPolicyResource is a helper function to simplify the provider implementation.
+func NewCiliumLocalRedirectPolicyResource() resource.Resource {
+	return &ciliumLocalRedirectPolicyResource{}
+}
+
+// ciliumLocalRedirectPolicyResourceModel maps the resource schema data.
+type ciliumLocalRedirectPolicyResourceModel struct {
+	ID       types.String                  `tfsdk:"id"`
+	Metadata *objectMetaModel              `tfsdk:"metadata"`
+	Spec     *localRedirectPolicySpecModel `tfsdk:"spec"`
+}
+
+type localRedirectPolicySpecModel struct {
+	RedirectFrontend        *redirectFrontendModel `tfsdk:"redirect_frontend"`
+	RedirectBackend         *redirectBackendModel  `tfsdk:"redirect_backend"`
+	SkipRedirectFromBackend types.Bool             `tfsdk:"skip_redirect_from_backend"`
+	Description             types.String           `tfsdk:"description"`
+}
+
+type redirectFrontendModel struct {
+	AddressMatcher *addressMatcherModel `tfsdk:"address_matcher"`
+	ServiceMatcher *serviceMatcherModel `tfsdk:"service_matcher"`
+}
+
+type addressMatcherModel struct {
+	IP      types.String    `tfsdk:"ip"`
+	ToPorts []portInfoModel `tfsdk:"to_ports"`
+}
+
+type serviceMatcherModel struct {
+	ServiceName types.String    `tfsdk:"service_name"`
+	Namespace   types.String    `tfsdk:"namespace"`
+	ToPorts     []portInfoModel `tfsdk:"to_ports"`
+}
+
+type redirectBackendModel struct {
+	LocalEndpointSelector *selectorModel  `tfsdk:"local_endpoint_selector"`
+	ToPorts               []portInfoModel `tfsdk:"to_ports"`
+}
+
+type portInfoModel struct {
+	Port     types.String `tfsdk:"port"`
+	Protocol types.String `tfsdk:"protocol"`
+	Name     types.String `tfsdk:"name"`
+}
+
+// ciliumLocalRedirectPolicyResource is the resource implementation.
+type ciliumLocalRedirectPolicyResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumLocalRedirectPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumLocalRedirectPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_local_redirect_policy"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumLocalRedirectPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages a CiliumLocalRedirectPolicy, which redirects traffic sent to a frontend to backend pods on the same node.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the policy in namespace/name form.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata": metadataAttribute(true),
+			"spec": schema.SingleNestedAttribute{
+				Description: "The redirection.",
+				Required:    true,
+				Attributes: map[string]schema.Attribute{
+					"redirect_frontend": schema.SingleNestedAttribute{
+						Description: "Traffic to redirect, matched either by address or by Kubernetes service.",
+						Required:    true,
+						Attributes: map[string]schema.Attribute{
+							"address_matcher": schema.SingleNestedAttribute{
+								Description: "Redirect traffic sent to an IP address. Conflicts with `service_matcher`.",
+								Optional:    true,
+								Attributes: map[string]schema.Attribute{
+									"ip": schema.StringAttribute{
+										Description: "IPv4 or IPv6 address of the frontend.",
+										Required:    true,
+									},
+									"to_ports": portInfoListAttribute("Ports of the frontend.", true),
+								},
+							},
+							"service_matcher": schema.SingleNestedAttribute{
+								Description: "Redirect traffic sent to a Kubernetes service. Conflicts with `address_matcher`.",
+								Optional:    true,
+								Attributes: map[string]schema.Attribute{
+									"service_name": schema.StringAttribute{
+										Description: "Name of the service.",
+										Required:    true,
+									},
+									"namespace": schema.StringAttribute{
+										Description: "Namespace of the service, which must be the namespace of the policy.",
+										Required:    true,
+									},
+									"to_ports": portInfoListAttribute("Ports of the service to redirect. Defaults to all of them.", false),
+								},
+							},
+						},
+					},
+					"redirect_backend": schema.SingleNestedAttribute{
+						Description: "Node-local pods the traffic is redirected to.",
+						Required:    true,
+						Attributes: map[string]schema.Attribute{
+							"local_endpoint_selector": schema.SingleNestedAttribute{
+								Description: "Selects the backend pods by their labels.",
+								Required:    true,
+								Attributes:  selectorAttributes(),
+							},
+							"to_ports": portInfoListAttribute("Ports of the backend pods.", true),
+						},
+					},
+					"skip_redirect_from_backend": schema.BoolAttribute{
+						Description: "Do not redirect traffic sent to the frontend by the backend pods themselves, " +
+							"e.g. node-local DNS caches forwarding to the cluster DNS service. Requires Cilium 1.16 or newer.",
+						Optional: true,
+					},
+					"description": schema.StringAttribute{
+						Description: "Free form description of the policy.",
+						Optional:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func portInfoListAttribute(description string, required bool) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: description,
+		Required:    required,
+		Optional:    !required,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"port": schema.StringAttribute{
+					Description: "Port number.",
+					Required:    true,
+				},
+				"protocol": schema.StringAttribute{
+					Description: "One of TCP and UDP.",
+					Required:    true,
+				},
+				"name": schema.StringAttribute{
+					Description: "Name of the port, required when there are several ports to match frontend and backend ports.",
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that the frontend is matched either by address or
+// by service, that a matched address is an IP and that a matched service is
+// in the namespace of the policy.
+func (r *ciliumLocalRedirectPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	frontend := path.Root("spec").AtName("redirect_frontend")
+
+	var addressMatcher, serviceMatcher types.Object
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, frontend.AtName("address_matcher"), &addressMatcher)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, frontend.AtName("service_matcher"), &serviceMatcher)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !addressMatcher.IsUnknown() && !serviceMatcher.IsUnknown() && addressMatcher.IsNull() == serviceMatcher.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			frontend,
+			"Invalid Redirect Frontend",
+			"Exactly one of address_matcher and service_matcher must be set.",
+		)
+	}
+
+	var ip types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, frontend.AtName("address_matcher").AtName("ip"), &ip)...)
+	if !ip.IsNull() && !ip.IsUnknown() && net.ParseIP(ip.ValueString()) == nil {
+		resp.Diagnostics.AddAttributeError(
+			frontend.AtName("address_matcher").AtName("ip"),
+			"Invalid Redirect Frontend",
+			fmt.Sprintf("%q is not an IP address.", ip.ValueString()),
+		)
+	}
+
+	// The agent ignores policies matching a service of another namespace,
+	// which the API server accepts.
+	var policyNamespace, serviceNamespace types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("metadata").AtName("namespace"), &policyNamespace)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, frontend.AtName("service_matcher").AtName("namespace"), &serviceNamespace)...)
+	if policyNamespace.IsUnknown() || serviceNamespace.IsNull() || serviceNamespace.IsUnknown() {
+		return
+	}
+	namespace := policyNamespace.ValueString()
+	if policyNamespace.IsNull() {
+		namespace = defaultNamespace
+	}
+	if serviceNamespace.ValueString() != namespace {
+		resp.Diagnostics.AddAttributeError(
+			frontend.AtName("service_matcher").AtName("namespace"),
+			"Invalid Redirect Frontend",
+			fmt.Sprintf("The service must be in the namespace of the policy, %q, got %q.", namespace, serviceNamespace.ValueString()),
+		)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumLocalRedirectPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumLocalRedirectPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, err := plan.Spec.expand()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Local Redirect Policy", err.Error())
+		return
+	}
+	clrp := &ciliumLocalRedirectPolicy{
+		ObjectMeta: plan.Metadata.objectMeta(),
+		Spec:       spec,
+	}
+
+	created, err := r.client.CreateCiliumLocalRedirectPolicy(ctx, clrp)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Cilium Local Redirect Policy", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Created CiliumLocalRedirectPolicy", map[string]interface{}{"namespace": created.Namespace, "name": created.Name})
+
+	plan.ID = types.StringValue(namespacedID(created.Namespace, created.Name))
+	plan.Metadata.Namespace = types.StringValue(created.Namespace)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumLocalRedirectPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumLocalRedirectPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseNamespacedID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Local Redirect Policy ID", err.Error())
+		return
+	}
+
+	clrp, err := r.client.GetCiliumLocalRedirectPolicy(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, "CiliumLocalRedirectPolicy not found, removing it from the state", map[string]interface{}{"namespace": namespace, "name": name})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Local Redirect Policy", kubernetesErrorDetail(err))
+		return
+	}
+
+	refreshed := flattenCiliumLocalRedirectPolicy(clrp)
+	// skipRedirectFromBackend is omitted when false, keep an explicit false.
+	if state.Spec != nil && !state.Spec.SkipRedirectFromBackend.IsNull() && !clrp.Spec.SkipRedirectFromBackend {
+		refreshed.Spec.SkipRedirectFromBackend = types.BoolValue(false)
+	}
+	if state.Spec != nil && state.Spec.RedirectBackend != nil {
+		keepEmptyMatchLabels(refreshed.Spec.RedirectBackend.LocalEndpointSelector, state.Spec.RedirectBackend.LocalEndpointSelector)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, refreshed)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumLocalRedirectPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumLocalRedirectPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	spec, err := plan.Spec.expand()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Local Redirect Policy", err.Error())
+		return
+	}
+
+	meta := plan.Metadata.objectMeta()
+	updated, err := r.client.UpdateCiliumLocalRedirectPolicy(ctx, meta.Namespace, meta.Name, func(clrp *ciliumLocalRedirectPolicy) {
+		clrp.Labels = meta.Labels
+		clrp.Annotations = meta.Annotations
+		clrp.Spec = spec
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Cilium Local Redirect Policy", kubernetesErrorDetail(err))
+		return
+	}
+
+	plan.ID = types.StringValue(namespacedID(updated.Namespace, updated.Name))
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumLocalRedirectPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumLocalRedirectPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseNamespacedID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Local Redirect Policy ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteCiliumLocalRedirectPolicy(ctx, namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Delete Cilium Local Redirect Policy", kubernetesErrorDetail(err))
+	}
+}
+
+// ImportState imports a policy by its namespace/name. A bare name imports
+// the policy from the default namespace.
+func (r *ciliumLocalRedirectPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	namespace, name, err := parseNamespacedID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), namespacedID(namespace, name))...)
+}
+
+// expand converts the spec model into a CiliumLocalRedirectPolicy spec,
+// checking the ports the way the agent does.
+func (m *localRedirectPolicySpecModel) expand() (localRedirectPolicySpec, error) {
+	spec := localRedirectPolicySpec{
+		CiliumLocalRedirectPolicySpec: ciliumv2.CiliumLocalRedirectPolicySpec{
+			RedirectBackend: ciliumv2.RedirectBackend{
+				LocalEndpointSelector: expandLabelSelector(m.RedirectBackend.LocalEndpointSelector),
+				ToPorts:               expandPortInfos(m.RedirectBackend.ToPorts),
+			},
+			Description: m.Description.ValueString(),
+		},
+		SkipRedirectFromBackend: m.SkipRedirectFromBackend.ValueBool(),
+	}
+	if err := checkPortInfos("redirect_backend", spec.RedirectBackend.ToPorts); err != nil {
+		return spec, err
+	}
+
+	switch frontend := m.RedirectFrontend; {
+	case frontend.AddressMatcher != nil && frontend.ServiceMatcher == nil:
+		spec.RedirectFrontend.AddressMatcher = &ciliumv2.Frontend{
+			IP:      frontend.AddressMatcher.IP.ValueString(),
+			ToPorts: expandPortInfos(frontend.AddressMatcher.ToPorts),
+		}
+		if err := checkPortInfos("redirect_frontend.address_matcher", spec.RedirectFrontend.AddressMatcher.ToPorts); err != nil {
+			return spec, err
+		}
+	case frontend.ServiceMatcher != nil && frontend.AddressMatcher == nil:
+		spec.RedirectFrontend.ServiceMatcher = &ciliumv2.ServiceInfo{
+			Name:      frontend.ServiceMatcher.ServiceName.ValueString(),
+			Namespace: frontend.ServiceMatcher.Namespace.ValueString(),
+			ToPorts:   expandPortInfos(frontend.ServiceMatcher.ToPorts),
+		}
+		if err := checkPortInfos("redirect_frontend.service_matcher", spec.RedirectFrontend.ServiceMatcher.ToPorts); err != nil {
+			return spec, err
+		}
+	default:
+		return spec, fmt.Errorf("exactly one of address_matcher and service_matcher must be set")
+	}
+	return spec, nil
+}
+
+// checkPortInfos checks ports as the agent does when it imports the policy:
+// every port must be valid and named when there are several of them.
+func checkPortInfos(attribute string, ports []ciliumv2.PortInfo) error {
+	for i := range ports {
+		if _, _, _, err := ports[i].SanitizePortInfo(len(ports) > 1); err != nil {
+			return fmt.Errorf("%s.to_ports[%d]: %w", attribute, i, err)
+		}
+	}
+	return nil
+}
+
+func expandPortInfos(models []portInfoModel) []ciliumv2.PortInfo {
+	var ports []ciliumv2.PortInfo
+	for _, m := range models {
+		ports = append(ports, ciliumv2.PortInfo{
+			Port:     m.Port.ValueString(),
+			Protocol: api.L4Proto(m.Protocol.ValueString()),
+			Name:     m.Name.ValueString(),
+		})
+	}
+	return ports
+}
+
+func flattenPortInfos(ports []ciliumv2.PortInfo) []portInfoModel {
+	var models []portInfoModel
+	for _, port := range ports {
+		models = append(models, portInfoModel{
+			Port:     types.StringValue(port.Port),
+			Protocol: types.StringValue(string(port.Protocol)),
+			Name:     stringValueOrNull(port.Name),
+		})
+	}
+	return models
+}
+
+// flattenCiliumLocalRedirectPolicy converts a CiliumLocalRedirectPolicy into
+// the resource model.
+func flattenCiliumLocalRedirectPolicy(clrp *ciliumLocalRedirectPolicy) ciliumLocalRedirectPolicyResourceModel {
+	spec := &clrp.Spec
+	m := ciliumLocalRedirectPolicyResourceModel{
+		ID:       types.StringValue(namespacedID(clrp.Namespace, clrp.Name)),
+		Metadata: flattenObjectMeta(clrp.ObjectMeta),
+		Spec: &localRedirectPolicySpecModel{
+			RedirectFrontend: &redirectFrontendModel{},
+			RedirectBackend: &redirectBackendModel{
+				LocalEndpointSelector: flattenLabelSelector(spec.RedirectBackend.LocalEndpointSelector),
+				ToPorts:               flattenPortInfos(spec.RedirectBackend.ToPorts),
+			},
+			SkipRedirectFromBackend: types.BoolNull(),
+			Description:             stringValueOrNull(spec.Description),
+		},
+	}
+	if spec.SkipRedirectFromBackend {
+		m.Spec.SkipRedirectFromBackend = types.BoolValue(true)
+	}
+	if frontend := spec.RedirectFrontend.AddressMatcher; frontend != nil {
+		m.Spec.RedirectFrontend.AddressMatcher = &addressMatcherModel{
+			IP:      types.StringValue(frontend.IP),
+			ToPorts: flattenPortInfos(frontend.ToPorts),
+		}
+	}
+	if frontend := spec.RedirectFrontend.ServiceMatcher; frontend != nil {
+		m.Spec.RedirectFrontend.ServiceMatcher = &serviceMatcherModel{
+			ServiceName: types.StringValue(frontend.Name),
+			Namespace:   types.StringValue(frontend.Namespace),
+			ToPorts:     flattenPortInfos(frontend.ToPorts),
+		}
+	}
+	return m
+}
+
+// expandLabelSelector converts a selector of Kubernetes objects by their
+// labels. Unlike endpoint selectors, keys are used unchanged.
+func expandLabelSelector(m *selectorModel) slimv1.LabelSelector {
+	selector := slimv1.LabelSelector{MatchLabels: m.MatchLabels}
+	for _, expr := range m.MatchExpressions {
+		selector.MatchExpressions = append(selector.MatchExpressions, slimv1.LabelSelectorRequirement{
+			Key:      expr.Key.ValueString(),
+			Operator: slimv1.LabelSelectorOperator(expr.Operator.ValueString()),
+			Values:   expr.Values,
+		})
+	}
+	return selector
+}
+
+// keepEmptyMatchLabels keeps an empty match_labels of prior in refreshed.
+// Empty maps are omitted by the API and flattened as null, which would show
+// as a diff against the configuration.
+func keepEmptyMatchLabels(refreshed, prior *selectorModel) {
+	if refreshed != nil && prior != nil && prior.MatchLabels != nil && len(prior.MatchLabels) == 0 && refreshed.MatchLabels == nil {
+		refreshed.MatchLabels = map[string]string{}
+	}
+}
+
+// flattenLabelSelector reverses expandLabelSelector.
+func flattenLabelSelector(selector slimv1.LabelSelector) *selectorModel {
+	m := &selectorModel{MatchLabels: nilIfEmptyMap(selector.MatchLabels)}
+	for _, expr := range selector.MatchExpressions {
+		m.MatchExpressions = append(m.MatchExpressions, selectorRequirementModel{
+			Key:      types.StringValue(expr.Key),
+			Operator: types.StringValue(string(expr.Operator)),
+			Values:   nilIfEmpty(expr.Values),
+		})
+	}
+	return m
+}
@@ -0,0 +1,286 @@
+package cilium
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	tfresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAccCiliumLocalRedirectPolicyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_local_redirect_policy" "test" {
+  metadata = {
+    name      = "tf-acc-nodelocaldns"
+    namespace = "kube-system"
+  }
+  spec = {
+    redirect_frontend = {
+      service_matcher = {
+        service_name = "kube-dns"
+        namespace    = "kube-system"
+      }
+    }
+    redirect_backend = {
+      local_endpoint_selector = {
+        match_labels = {
+          k8s-app = "node-local-dns"
+        }
+      }
+      to_ports = [
+        { port = "53", protocol = "UDP", name = "dns" },
+        { port = "53", protocol = "TCP", name = "dns-tcp" },
+      ]
+    }
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_local_redirect_policy.test", "id", "kube-system/tf-acc-nodelocaldns"),
+					resource.TestCheckResourceAttrSet("cilium_local_redirect_policy.test", "metadata.uid"),
+					resource.TestCheckResourceAttr("cilium_local_redirect_policy.test", "spec.redirect_backend.to_ports.#", "2"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_local_redirect_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCiliumLocalRedirectPolicyResource(t *testing.T) {
+	ctx := context.Background()
+	client := testDynamicClient(t, `
+apiVersion: cilium.io/v2
+kind: CiliumLocalRedirectPolicy
+metadata:
+  name: nodelocaldns
+  namespace: kube-system
+  annotations:
+    team: platform
+spec:
+  redirectFrontend:
+    serviceMatcher:
+      serviceName: kube-dns
+      namespace: kube-system
+  redirectBackend:
+    localEndpointSelector:
+      matchLabels:
+        k8s-app: node-local-dns
+    toPorts:
+    - port: "53"
+      name: dns
+      protocol: UDP
+    - port: "53"
+      name: dns-tcp
+      protocol: TCP
+  skipRedirectFromBackend: true
+`)
+
+	state, diags := testImportResource(t, NewCiliumLocalRedirectPolicyResource(), client, "kube-system/nodelocaldns")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumLocalRedirectPolicyResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "kube-system/nodelocaldns" || model.Metadata.Annotations["team"] != "platform" {
+		t.Errorf("unexpected id %s or metadata %+v", model.ID, model.Metadata)
+	}
+	spec := model.Spec
+	if spec.RedirectFrontend.AddressMatcher != nil || spec.RedirectFrontend.ServiceMatcher.ServiceName.ValueString() != "kube-dns" {
+		t.Errorf("unexpected frontend %+v", spec.RedirectFrontend)
+	}
+	if !spec.SkipRedirectFromBackend.ValueBool() || len(spec.RedirectBackend.ToPorts) != 2 ||
+		spec.RedirectBackend.ToPorts[1].Name.ValueString() != "dns-tcp" ||
+		spec.RedirectBackend.LocalEndpointSelector.MatchLabels["k8s-app"] != "node-local-dns" {
+		t.Errorf("unexpected spec %+v", spec)
+	}
+
+	// Switch the frontend to an address, as an update would.
+	spec.RedirectFrontend = &redirectFrontendModel{
+		AddressMatcher: &addressMatcherModel{
+			IP:      types.StringValue("169.254.20.10"),
+			ToPorts: spec.RedirectBackend.ToPorts,
+		},
+	}
+	spec.SkipRedirectFromBackend = types.BoolNull()
+	expanded, err := spec.expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated, err := client.UpdateCiliumLocalRedirectPolicy(ctx, "kube-system", "nodelocaldns", func(clrp *ciliumLocalRedirectPolicy) {
+		clrp.Spec = expanded
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := updated.toUnstructured()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ip, _, _ := unstructured.NestedString(obj.Object, "spec", "redirectFrontend", "addressMatcher", "ip"); ip != "169.254.20.10" {
+		t.Errorf("addressMatcher.ip = %q", ip)
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "skipRedirectFromBackend"); found {
+		t.Error("skipRedirectFromBackend should be omitted once unset")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "redirectFrontend", "serviceMatcher"); found {
+		t.Error("serviceMatcher should be removed")
+	}
+
+	if err := client.DeleteCiliumLocalRedirectPolicy(ctx, "kube-system", "nodelocaldns"); err != nil {
+		t.Fatal(err)
+	}
+	state, diags = testImportResource(t, NewCiliumLocalRedirectPolicyResource(), client, "kube-system/nodelocaldns")
+	if diags.HasError() || !state.Raw.IsNull() {
+		t.Errorf("expected a deleted policy to be removed from the state, got %v", diags)
+	}
+}
+
+func TestCiliumLocalRedirectPolicyExpandPorts(t *testing.T) {
+	backend := &redirectBackendModel{
+		LocalEndpointSelector: &selectorModel{MatchLabels: map[string]string{"app": "proxy"}},
+		ToPorts: []portInfoModel{
+			{Port: types.StringValue("80"), Protocol: types.StringValue("TCP")},
+			{Port: types.StringValue("443"), Protocol: types.StringValue("TCP")},
+		},
+	}
+	spec := &localRedirectPolicySpecModel{
+		RedirectFrontend: &redirectFrontendModel{
+			AddressMatcher: &addressMatcherModel{
+				IP:      types.StringValue("10.0.0.1"),
+				ToPorts: []portInfoModel{{Port: types.StringValue("80"), Protocol: types.StringValue("TCP")}},
+			},
+		},
+		RedirectBackend: backend,
+	}
+	if _, err := spec.expand(); err == nil || !strings.Contains(err.Error(), "redirect_backend.to_ports[0]") {
+		t.Errorf("expected an error about unnamed backend ports, got %v", err)
+	}
+
+	backend.ToPorts = backend.ToPorts[:1]
+	backend.ToPorts[0].Port = types.StringValue("http")
+	if _, err := spec.expand(); err == nil {
+		t.Error("expected an error about an invalid port number")
+	}
+}
+
+func TestCiliumLocalRedirectPolicyValidateConfig(t *testing.T) {
+	ctx := context.Background()
+	r := NewCiliumLocalRedirectPolicyResource().(tfresource.ResourceWithValidateConfig)
+
+	var schemaResp tfresource.SchemaResponse
+	r.Schema(ctx, tfresource.SchemaRequest{}, &schemaResp)
+	frontend := path.Root("spec").AtName("redirect_frontend")
+	ports := []portInfoModel{{Port: types.StringValue("53"), Protocol: types.StringValue("UDP"), Name: types.StringNull()}}
+	address := func(ip string) *addressMatcherModel {
+		return &addressMatcherModel{IP: types.StringValue(ip), ToPorts: ports}
+	}
+	service := &serviceMatcherModel{ServiceName: types.StringValue("kube-dns"), Namespace: types.StringValue("kube-system")}
+
+	defaultService := &serviceMatcherModel{ServiceName: types.StringValue("web"), Namespace: types.StringValue("default")}
+
+	for name, tc := range map[string]struct {
+		namespace string
+		frontend  redirectFrontendModel
+		wantErr   string
+	}{
+		"address":            {frontend: redirectFrontendModel{AddressMatcher: address("169.254.20.10")}},
+		"service":            {namespace: "kube-system", frontend: redirectFrontendModel{ServiceMatcher: service}},
+		"default namespace":  {frontend: redirectFrontendModel{ServiceMatcher: defaultService}},
+		"neither":            {wantErr: "Exactly one of address_matcher and service_matcher must be set."},
+		"both":               {namespace: "kube-system", frontend: redirectFrontendModel{AddressMatcher: address("169.254.20.10"), ServiceMatcher: service}, wantErr: "Exactly one of address_matcher and service_matcher must be set."},
+		"invalid ip":         {frontend: redirectFrontendModel{AddressMatcher: address("kube-dns")}, wantErr: `"kube-dns" is not an IP address.`},
+		"other namespace":    {namespace: "apps", frontend: redirectFrontendModel{ServiceMatcher: service}, wantErr: `The service must be in the namespace of the policy, "apps", got "kube-system".`},
+		"implicit namespace": {frontend: redirectFrontendModel{ServiceMatcher: service}, wantErr: `The service must be in the namespace of the policy, "default", got "kube-system".`},
+	} {
+		// Build the configuration through a state, which creates the
+		// parents of the attribute being set.
+		state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+		if diags := state.SetAttribute(ctx, frontend, tc.frontend); diags.HasError() {
+			t.Fatal(diags)
+		}
+		if tc.namespace != "" {
+			if diags := state.SetAttribute(ctx, path.Root("metadata").AtName("namespace"), tc.namespace); diags.HasError() {
+				t.Fatal(diags)
+			}
+		}
+
+		var resp tfresource.ValidateConfigResponse
+		r.ValidateConfig(ctx, tfresource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: state.Raw},
+		}, &resp)
+		switch {
+		case tc.wantErr == "" && resp.Diagnostics.HasError():
+			t.Errorf("%s: unexpected diagnostics %v", name, resp.Diagnostics)
+		case tc.wantErr != "" && (!resp.Diagnostics.HasError() || resp.Diagnostics[0].Detail() != tc.wantErr):
+			t.Errorf("%s: got diagnostics %v, want %q", name, resp.Diagnostics, tc.wantErr)
+		}
+	}
+}
+
+func TestCiliumLocalRedirectPolicyReadKeepsEmptyMatchLabels(t *testing.T) {
+	ctx := context.Background()
+	client := testDynamicClient(t, `
+apiVersion: cilium.io/v2
+kind: CiliumLocalRedirectPolicy
+metadata:
+  name: proxy
+  namespace: default
+spec:
+  redirectFrontend:
+    addressMatcher:
+      ip: 169.254.169.254
+      toPorts:
+      - port: "80"
+        protocol: TCP
+  redirectBackend:
+    localEndpointSelector:
+      matchExpressions:
+      - key: app
+        operator: Exists
+    toPorts:
+    - port: "8080"
+      protocol: TCP
+`)
+	r := NewCiliumLocalRedirectPolicyResource()
+	state, diags := testImportResource(t, r, client, "default/proxy")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// Configure match_labels = {}, which the API omits.
+	selector := path.Root("spec").AtName("redirect_backend").AtName("local_endpoint_selector").AtName("match_labels")
+	if diags := state.SetAttribute(ctx, selector, map[string]string{}); diags.HasError() {
+		t.Fatal(diags)
+	}
+	resp := tfresource.ReadResponse{State: state}
+	r.Read(ctx, tfresource.ReadRequest{State: state}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatal(resp.Diagnostics)
+	}
+	var matchLabels map[string]string
+	if diags := resp.State.GetAttribute(ctx, selector, &matchLabels); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if matchLabels == nil || len(matchLabels) != 0 {
+		t.Errorf("match_labels refreshed as %v, want an empty map", matchLabels)
+	}
+}
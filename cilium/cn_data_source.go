@@ -2,19 +2,31 @@ package cilium
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eniTypes "github.com/cilium/cilium/pkg/aws/eni/types"
+	ipamTypes "github.com/cilium/cilium/pkg/ipam/types"
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ datasource.DataSource              = &ciliumNodeDataSource{}
-	_ datasource.DataSourceWithConfigure = &ciliumNodeDataSource{}
+	_ datasource.DataSource                   = &ciliumNodeDataSource{}
+	_ datasource.DataSourceWithConfigure      = &ciliumNodeDataSource{}
+	_ datasource.DataSourceWithValidateConfig = &ciliumNodeDataSource{}
 )
 
+// defaultCiliumNodePageSize is the number of CiliumNodes requested at once
+// when listing nodes, matching the default chunk size of kubectl.
+const defaultCiliumNodePageSize = 500
+
 // NewCiliumNodeDataSource is a helper function to simplify the provider implementation.
 func NewCiliumNodeDataSource() datasource.DataSource {
 	return &ciliumNodeDataSource{}
@@ -33,25 +45,489 @@ func (d *ciliumNodeDataSource) Metadata(_ context.Context, req datasource.Metada
 // Schema defines the schema for the data source.
 func (d *ciliumNodeDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Lists CiliumNodes.",
 		Attributes: map[string]schema.Attribute{
 			"id": schema.StringAttribute{
-				Computed: true,
+				Description: "Identifier of the listed nodes, which changes whenever a node is added, removed or modified.",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the names, labels, annotations, spec and status of the listed nodes.",
+				Computed:    true,
+			},
+			"label_selector": schema.StringAttribute{
+				Description: "Kubernetes label selector restricting the listed nodes, e.g. `node-role.kubernetes.io/control-plane`.",
+				Optional:    true,
+			},
+			"field_selector": schema.StringAttribute{
+				Description: "Kubernetes field selector restricting the listed nodes, e.g. `metadata.name=worker-1`.",
+				Optional:    true,
+			},
+			"page_size": schema.Int64Attribute{
+				Description: fmt.Sprintf("Number of nodes requested from the API server at once. Defaults to %d.", defaultCiliumNodePageSize),
+				Optional:    true,
 			},
 			"ciliumnodes": schema.ListNestedAttribute{
-				Computed: true,
+				Description: "The matching CiliumNodes.",
+				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"apiversion": schema.StringAttribute{
-							Computed: true,
-						},
-						"kind": schema.StringAttribute{
-							Computed: true,
+					Attributes: ciliumNodeAttributes(),
+				},
+			},
+		},
+	}
+}
+
+// ciliumNodeAttributes returns the schema of a CiliumNode read by a data
+// source.
+func ciliumNodeAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"apiversion": schema.StringAttribute{
+			Computed: true,
+		},
+		"kind": schema.StringAttribute{
+			Computed: true,
+		},
+		"metadata": dataSourceMetadataAttribute(),
+		"spec": schema.SingleNestedAttribute{
+			Description: "Configuration of the node.",
+			Computed:    true,
+			Attributes:  ciliumNodeSpecAttributes(),
+		},
+		"status": schema.SingleNestedAttribute{
+			Description: "Status of the node as reported by the Cilium agent and operator.",
+			Computed:    true,
+			Attributes:  ciliumNodeStatusAttributes(),
+		},
+	}
+}
+
+func ciliumNodeSpecAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"instance_id": schema.StringAttribute{
+			Description: "Identifier of the node used by the cloud provider, if any.",
+			Computed:    true,
+		},
+		"node_identity": schema.Int64Attribute{
+			Description: "Numeric security identity allocated for the node, if any.",
+			Computed:    true,
+		},
+		"addresses": schema.ListNestedAttribute{
+			Description: "Addresses of the node.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"type": schema.StringAttribute{
+						Description: "Type of the address, e.g. `InternalIP`, `CiliumInternalIP` or `ExternalIP`.",
+						Computed:    true,
+					},
+					"ip": schema.StringAttribute{
+						Description: "The IP address.",
+						Computed:    true,
+					},
+				},
+			},
+		},
+		"health": schema.SingleNestedAttribute{
+			Description: "Addresses of the health endpoint of the node.",
+			Computed:    true,
+			Attributes:  addressPairAttributes(),
+		},
+		"ingress": schema.SingleNestedAttribute{
+			Description: "Addresses of the Ingress listener of the node.",
+			Computed:    true,
+			Attributes:  addressPairAttributes(),
+		},
+		"encryption": schema.SingleNestedAttribute{
+			Description: "Encryption settings of the node.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"key": schema.Int64Attribute{
+					Description: "Index of the key used for encryption, 0 if encryption is disabled.",
+					Computed:    true,
+				},
+			},
+		},
+		"ipam": schema.SingleNestedAttribute{
+			Description: "IPAM settings of the node.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"pool": schema.MapNestedAttribute{
+					Description: "IPs available to the node, keyed by IP.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: allocationIPAttributes(),
+					},
+				},
+				"pod_cidrs": schema.ListAttribute{
+					Description: "CIDRs from which pod IPs are allocated.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"min_allocate": schema.Int64Attribute{
+					Description: "Minimum number of IPs allocated when the node is first bootstrapped.",
+					Computed:    true,
+				},
+				"max_allocate": schema.Int64Attribute{
+					Description: "Maximum number of IPs which can be allocated to the node.",
+					Computed:    true,
+				},
+				"pre_allocate": schema.Int64Attribute{
+					Description: "Number of IPs kept available for immediate allocation.",
+					Computed:    true,
+				},
+				"max_above_watermark": schema.Int64Attribute{
+					Description: "Maximum number of addresses allocated beyond the addresses needed to reach pre_allocate.",
+					Computed:    true,
+				},
+				"pod_cidr_allocation_threshold": schema.Int64Attribute{
+					Description: "Number of free IPs below which a new pod CIDR is requested.",
+					Computed:    true,
+				},
+				"pod_cidr_release_threshold": schema.Int64Attribute{
+					Description: "Number of free IPs above which an unused pod CIDR is released.",
+					Computed:    true,
+				},
+			},
+		},
+		"eni": schema.SingleNestedAttribute{
+			Description: "AWS ENI specific configuration.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"instance_id": schema.StringAttribute{
+					Description: "AWS EC2 instance ID of the node.",
+					Computed:    true,
+				},
+				"instance_type": schema.StringAttribute{
+					Description: "AWS EC2 instance type of the node.",
+					Computed:    true,
+				},
+				"min_allocate": schema.Int64Attribute{
+					Description: "Minimum number of IPs allocated when the node is first bootstrapped.",
+					Computed:    true,
+				},
+				"pre_allocate": schema.Int64Attribute{
+					Description: "Number of IPs kept available for immediate allocation.",
+					Computed:    true,
+				},
+				"max_above_watermark": schema.Int64Attribute{
+					Description: "Maximum number of addresses allocated beyond the addresses needed to reach pre_allocate.",
+					Computed:    true,
+				},
+				"first_interface_index": schema.Int64Attribute{
+					Description: "Index of the first ENI used for IP allocation.",
+					Computed:    true,
+				},
+				"security_groups": schema.ListAttribute{
+					Description: "Security groups attached to new ENIs.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"security_group_tags": schema.MapAttribute{
+					Description: "Tags selecting the security groups attached to new ENIs.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"subnet_ids": schema.ListAttribute{
+					Description: "Subnets in which new ENIs are created.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"subnet_tags": schema.MapAttribute{
+					Description: "Tags selecting the subnets in which new ENIs are created.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"node_subnet_id": schema.StringAttribute{
+					Description: "Subnet of the primary ENI of the node.",
+					Computed:    true,
+				},
+				"vpc_id": schema.StringAttribute{
+					Description: "VPC of the node.",
+					Computed:    true,
+				},
+				"availability_zone": schema.StringAttribute{
+					Description: "Availability zone of the node.",
+					Computed:    true,
+				},
+				"exclude_interface_tags": schema.MapAttribute{
+					Description: "Tags of the ENIs which are not used for IP allocation.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"delete_on_termination": schema.BoolAttribute{
+					Description: "Whether ENIs are deleted when the instance is terminated.",
+					Computed:    true,
+				},
+				"use_primary_address": schema.BoolAttribute{
+					Description: "Whether the primary address of ENIs is used for pods.",
+					Computed:    true,
+				},
+				"disable_prefix_delegation": schema.BoolAttribute{
+					Description: "Whether prefix delegation is disabled for the node.",
+					Computed:    true,
+				},
+			},
+		},
+		"azure": schema.SingleNestedAttribute{
+			Description: "Azure specific configuration.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"interface_name": schema.StringAttribute{
+					Description: "Name of the interface from which IPs are allocated.",
+					Computed:    true,
+				},
+			},
+		},
+		"alibaba_cloud": schema.SingleNestedAttribute{
+			Description: "AlibabaCloud specific configuration.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"instance_type": schema.StringAttribute{
+					Description: "ECS instance type of the node.",
+					Computed:    true,
+				},
+				"availability_zone": schema.StringAttribute{
+					Description: "Availability zone of the node.",
+					Computed:    true,
+				},
+				"vpc_id": schema.StringAttribute{
+					Description: "VPC of the node.",
+					Computed:    true,
+				},
+				"cidr_block": schema.StringAttribute{
+					Description: "CIDR of the VPC of the node.",
+					Computed:    true,
+				},
+				"vswitches": schema.ListAttribute{
+					Description: "vSwitches in which new ENIs are created.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"vswitch_tags": schema.MapAttribute{
+					Description: "Tags selecting the vSwitches in which new ENIs are created.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"security_groups": schema.ListAttribute{
+					Description: "Security groups attached to new ENIs.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"security_group_tags": schema.MapAttribute{
+					Description: "Tags selecting the security groups attached to new ENIs.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+			},
+		},
+	}
+}
+
+func ciliumNodeStatusAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"ipam": schema.SingleNestedAttribute{
+			Description: "IPAM status of the node.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"used": schema.MapNestedAttribute{
+					Description: "IPs allocated on the node, keyed by IP.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: allocationIPAttributes(),
+					},
+				},
+				"pod_cidrs": schema.MapAttribute{
+					Description: "Status of each pod CIDR of the node, keyed by CIDR.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"release_ips": schema.MapAttribute{
+					Description: "Status of the IPs being released, keyed by IP.",
+					ElementType: types.StringType,
+					Computed:    true,
+				},
+				"operator_error": schema.StringAttribute{
+					Description: "Last error reported by the operator for the node.",
+					Computed:    true,
+				},
+			},
+		},
+		"eni": schema.SingleNestedAttribute{
+			Description: "AWS ENI specific status.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"enis": schema.MapNestedAttribute{
+					Description: "ENIs attached to the node, keyed by ENI ID.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"id": schema.StringAttribute{
+								Computed: true,
+							},
+							"ip": schema.StringAttribute{
+								Description: "Primary IP of the ENI.",
+								Computed:    true,
+							},
+							"mac": schema.StringAttribute{
+								Computed: true,
+							},
+							"availability_zone": schema.StringAttribute{
+								Computed: true,
+							},
+							"description": schema.StringAttribute{
+								Computed: true,
+							},
+							"number": schema.Int64Attribute{
+								Description: "Interface index of the ENI on the instance.",
+								Computed:    true,
+							},
+							"subnet_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"subnet_cidr": schema.StringAttribute{
+								Computed: true,
+							},
+							"vpc_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"vpc_primary_cidr": schema.StringAttribute{
+								Computed: true,
+							},
+							"vpc_cidrs": schema.ListAttribute{
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"addresses": schema.ListAttribute{
+								Description: "Secondary IPs of the ENI.",
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"prefixes": schema.ListAttribute{
+								Description: "Prefixes delegated to the ENI.",
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"security_groups": schema.ListAttribute{
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"tags": schema.MapAttribute{
+								ElementType: types.StringType,
+								Computed:    true,
+							},
 						},
-						"metadata": schema.ObjectAttribute{
-							Computed: true,
+					},
+				},
+			},
+		},
+		"azure": schema.SingleNestedAttribute{
+			Description: "Azure specific status.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"interfaces": schema.ListNestedAttribute{
+					Description: "Interfaces attached to the node.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"id": schema.StringAttribute{
+								Computed: true,
+							},
+							"name": schema.StringAttribute{
+								Computed: true,
+							},
+							"mac": schema.StringAttribute{
+								Computed: true,
+							},
+							"state": schema.StringAttribute{
+								Computed: true,
+							},
+							"addresses": schema.ListNestedAttribute{
+								Description: "Secondary IPs of the interface.",
+								Computed:    true,
+								NestedObject: schema.NestedAttributeObject{
+									Attributes: map[string]schema.Attribute{
+										"ip": schema.StringAttribute{
+											Computed: true,
+										},
+										"subnet": schema.StringAttribute{
+											Computed: true,
+										},
+										"state": schema.StringAttribute{
+											Computed: true,
+										},
+									},
+								},
+							},
+							"security_group": schema.StringAttribute{
+								Computed: true,
+							},
+							"gateway": schema.StringAttribute{
+								Description: "Gateway of the subnet of the interface.",
+								Computed:    true,
+							},
+							"cidr": schema.StringAttribute{
+								Description: "CIDR of the subnet of the interface.",
+								Computed:    true,
+							},
 						},
-						"spec": schema.ObjectAttribute{
-							Computed: true,
+					},
+				},
+			},
+		},
+		"alibaba_cloud": schema.SingleNestedAttribute{
+			Description: "AlibabaCloud specific status.",
+			Computed:    true,
+			Attributes: map[string]schema.Attribute{
+				"enis": schema.MapNestedAttribute{
+					Description: "ENIs attached to the node, keyed by ENI ID.",
+					Computed:    true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"network_interface_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"mac_address": schema.StringAttribute{
+								Computed: true,
+							},
+							"type": schema.StringAttribute{
+								Description: "Type of the ENI, `Primary` or `Secondary`.",
+								Computed:    true,
+							},
+							"instance_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"security_group_ids": schema.ListAttribute{
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"vpc_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"vpc_cidr": schema.StringAttribute{
+								Computed: true,
+							},
+							"zone_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"vswitch_id": schema.StringAttribute{
+								Computed: true,
+							},
+							"vswitch_cidr": schema.StringAttribute{
+								Computed: true,
+							},
+							"primary_ip_address": schema.StringAttribute{
+								Computed: true,
+							},
+							"private_ip_addresses": schema.ListAttribute{
+								Description: "Secondary private IPs of the ENI.",
+								ElementType: types.StringType,
+								Computed:    true,
+							},
+							"tags": schema.MapAttribute{
+								ElementType: types.StringType,
+								Computed:    true,
+							},
 						},
 					},
 				},
@@ -60,35 +536,68 @@ func (d *ciliumNodeDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 	}
 }
 
+func addressPairAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"ipv4": schema.StringAttribute{
+			Computed: true,
+		},
+		"ipv6": schema.StringAttribute{
+			Computed: true,
+		},
+	}
+}
+
+func allocationIPAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"owner": schema.StringAttribute{
+			Description: "Owner of the IP once it is allocated.",
+			Computed:    true,
+		},
+		"resource": schema.StringAttribute{
+			Description: "Resource the IP is associated with, e.g. an ENI.",
+			Computed:    true,
+		},
+	}
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (d *ciliumNodeDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state ciliumNodeDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	cnl, err := d.client.ListCiliumNodes(ctx)
-	assertNoErrorTF(err, resp, "Unable to ListCiliumNodes")
+	pageSize := int64(defaultCiliumNodePageSize)
+	if !state.PageSize.IsNull() {
+		pageSize = state.PageSize.ValueInt64()
+	}
+	cnl, err := d.client.ListCiliumNodes(ctx, metav1.ListOptions{
+		LabelSelector: state.LabelSelector.ValueString(),
+		FieldSelector: state.FieldSelector.ValueString(),
+		Limit:         pageSize,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumNodes", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Listed CiliumNodes", map[string]interface{}{"count": len(cnl.Items)})
 
-	for _, cn := range cnl.Items {
-		ciliumNodeState := ciliumNodeModel{
-			ApiVersion: types.StringValue(cn.APIVersion),
-			Kind:       types.StringValue(cn.Kind),
-			Metadata: &metadataModel{
-				annotations: cn.Annotations,
-				labels:      cn.Labels,
-				name:        types.StringValue(cn.Name),
-				namespace:   types.StringValue(cn.Namespace),
-			},
-			Spec: &specModel{
-				instanceID: types.StringValue(cn.Spec.InstanceID),
-			},
-		}
-		tflog.Debug(ctx, "DBG state.CiliumNodes:::", map[string]interface{}{
-			"tf state:: ":          state.CiliumNodes,
-			"tf ciliumNodeState: ": ciliumNodeState,
-		})
-		state.CiliumNodes = append(state.CiliumNodes, ciliumNodeState)
+	state.CiliumNodes = nil
+	listed := make([]listedObject, 0, len(cnl.Items))
+	for i := range cnl.Items {
+		cn := &cnl.Items[i]
+		state.CiliumNodes = append(state.CiliumNodes, flattenCiliumNodeModel(cn))
+		listed = append(listed, newListedObject(cn.ObjectMeta, cn.Spec, cn.Status))
 	}
 
-	state.ID = types.StringValue("placeholder")
+	id, contentHash, err := listIdentity(d.client.clusterIdentity(), listed)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Hash CiliumNodes", err.Error())
+		return
+	}
+	state.ID = types.StringValue(id)
+	state.ContentHash = types.StringValue(contentHash)
 
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
@@ -97,6 +606,22 @@ func (d *ciliumNodeDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	}
 }
 
+// ValidateConfig rejects page sizes the API server would not honour.
+func (d *ciliumNodeDataSource) ValidateConfig(ctx context.Context, req datasource.ValidateConfigRequest, resp *datasource.ValidateConfigResponse) {
+	var pageSize types.Int64
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("page_size"), &pageSize)...)
+	if pageSize.IsNull() || pageSize.IsUnknown() {
+		return
+	}
+	if pageSize.ValueInt64() < 1 {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("page_size"),
+			"Invalid Page Size",
+			fmt.Sprintf("page_size must be at least 1, got %d.", pageSize.ValueInt64()),
+		)
+	}
+}
+
 // Configure enables provider-level data or clients to be set in the
 // provider-defined DataSource type. It is separately executed for each
 // ReadDataSource RPC.
@@ -104,30 +629,378 @@ func (d *ciliumNodeDataSource) Configure(_ context.Context, req datasource.Confi
 	if req.ProviderData == nil {
 		return
 	}
-	d.client = req.ProviderData.(*CiliumClient)
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
 }
 
-// coffeesDataSourceModel maps the data source schema data.
+// ciliumNodeDataSourceModel maps the data source schema data.
 type ciliumNodeDataSourceModel struct {
-	CiliumNodes []ciliumNodeModel `tfsdk:"ciliumnodes"`
-	ID          types.String      `tfsdk:"id"`
+	LabelSelector types.String      `tfsdk:"label_selector"`
+	FieldSelector types.String      `tfsdk:"field_selector"`
+	PageSize      types.Int64       `tfsdk:"page_size"`
+	CiliumNodes   []ciliumNodeModel `tfsdk:"ciliumnodes"`
+	ID            types.String      `tfsdk:"id"`
+	ContentHash   types.String      `tfsdk:"content_hash"`
 }
 
 // ciliumNodeModel maps ciliumnodes schema data.
 type ciliumNodeModel struct {
-	ApiVersion types.String   `tfsdk:"apiversion"`
-	Kind       types.String   `tfsdk:"kind"`
-	Metadata   *metadataModel `tfsdk:"metadata"`
-	Spec       *specModel     `tfsdk:"spec"`
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Spec       *ciliumNodeFullSpecModel   `tfsdk:"spec"`
+	Status     *ciliumNodeStatusModel     `tfsdk:"status"`
+}
+
+// ciliumNodeFullSpecModel maps the whole spec of a CiliumNode, unlike
+// ciliumNodeSpecModel which only holds the fields managed by the resource.
+type ciliumNodeFullSpecModel struct {
+	InstanceID   types.String                     `tfsdk:"instance_id"`
+	NodeIdentity types.Int64                      `tfsdk:"node_identity"`
+	Addresses    []nodeAddressModel               `tfsdk:"addresses"`
+	Health       *addressPairModel                `tfsdk:"health"`
+	Ingress      *addressPairModel                `tfsdk:"ingress"`
+	Encryption   *ciliumNodeEncryptionModel       `tfsdk:"encryption"`
+	IPAM         *ciliumNodeIPAMSpecModel         `tfsdk:"ipam"`
+	ENI          *ciliumNodeENISpecModel          `tfsdk:"eni"`
+	Azure        *ciliumNodeAzureSpecModel        `tfsdk:"azure"`
+	AlibabaCloud *ciliumNodeAlibabaCloudSpecModel `tfsdk:"alibaba_cloud"`
+}
+
+type nodeAddressModel struct {
+	Type types.String `tfsdk:"type"`
+	IP   types.String `tfsdk:"ip"`
+}
+
+type addressPairModel struct {
+	IPv4 types.String `tfsdk:"ipv4"`
+	IPv6 types.String `tfsdk:"ipv6"`
+}
+
+type ciliumNodeIPAMSpecModel struct {
+	Pool                       map[string]allocationIPModel `tfsdk:"pool"`
+	PodCIDRs                   []string                     `tfsdk:"pod_cidrs"`
+	MinAllocate                types.Int64                  `tfsdk:"min_allocate"`
+	MaxAllocate                types.Int64                  `tfsdk:"max_allocate"`
+	PreAllocate                types.Int64                  `tfsdk:"pre_allocate"`
+	MaxAboveWatermark          types.Int64                  `tfsdk:"max_above_watermark"`
+	PodCIDRAllocationThreshold types.Int64                  `tfsdk:"pod_cidr_allocation_threshold"`
+	PodCIDRReleaseThreshold    types.Int64                  `tfsdk:"pod_cidr_release_threshold"`
+}
+
+type ciliumNodeENISpecModel struct {
+	InstanceID              types.String      `tfsdk:"instance_id"`
+	InstanceType            types.String      `tfsdk:"instance_type"`
+	MinAllocate             types.Int64       `tfsdk:"min_allocate"`
+	PreAllocate             types.Int64       `tfsdk:"pre_allocate"`
+	MaxAboveWatermark       types.Int64       `tfsdk:"max_above_watermark"`
+	FirstInterfaceIndex     types.Int64       `tfsdk:"first_interface_index"`
+	SecurityGroups          []string          `tfsdk:"security_groups"`
+	SecurityGroupTags       map[string]string `tfsdk:"security_group_tags"`
+	SubnetIDs               []string          `tfsdk:"subnet_ids"`
+	SubnetTags              map[string]string `tfsdk:"subnet_tags"`
+	NodeSubnetID            types.String      `tfsdk:"node_subnet_id"`
+	VpcID                   types.String      `tfsdk:"vpc_id"`
+	AvailabilityZone        types.String      `tfsdk:"availability_zone"`
+	ExcludeInterfaceTags    map[string]string `tfsdk:"exclude_interface_tags"`
+	DeleteOnTermination     types.Bool        `tfsdk:"delete_on_termination"`
+	UsePrimaryAddress       types.Bool        `tfsdk:"use_primary_address"`
+	DisablePrefixDelegation types.Bool        `tfsdk:"disable_prefix_delegation"`
+}
+
+type ciliumNodeAzureSpecModel struct {
+	InterfaceName types.String `tfsdk:"interface_name"`
+}
+
+type ciliumNodeAlibabaCloudSpecModel struct {
+	InstanceType      types.String      `tfsdk:"instance_type"`
+	AvailabilityZone  types.String      `tfsdk:"availability_zone"`
+	VPCID             types.String      `tfsdk:"vpc_id"`
+	CIDRBlock         types.String      `tfsdk:"cidr_block"`
+	VSwitches         []string          `tfsdk:"vswitches"`
+	VSwitchTags       map[string]string `tfsdk:"vswitch_tags"`
+	SecurityGroups    []string          `tfsdk:"security_groups"`
+	SecurityGroupTags map[string]string `tfsdk:"security_group_tags"`
+}
+
+type ciliumNodeStatusModel struct {
+	IPAM         *ciliumNodeIPAMStatusModel         `tfsdk:"ipam"`
+	ENI          *ciliumNodeENIStatusModel          `tfsdk:"eni"`
+	Azure        *ciliumNodeAzureStatusModel        `tfsdk:"azure"`
+	AlibabaCloud *ciliumNodeAlibabaCloudStatusModel `tfsdk:"alibaba_cloud"`
+}
+
+type ciliumNodeIPAMStatusModel struct {
+	Used          map[string]allocationIPModel `tfsdk:"used"`
+	PodCIDRs      map[string]string            `tfsdk:"pod_cidrs"`
+	ReleaseIPs    map[string]string            `tfsdk:"release_ips"`
+	OperatorError types.String                 `tfsdk:"operator_error"`
+}
+
+type ciliumNodeENIStatusModel struct {
+	ENIs map[string]eniModel `tfsdk:"enis"`
+}
+
+type eniModel struct {
+	ID               types.String      `tfsdk:"id"`
+	IP               types.String      `tfsdk:"ip"`
+	MAC              types.String      `tfsdk:"mac"`
+	AvailabilityZone types.String      `tfsdk:"availability_zone"`
+	Description      types.String      `tfsdk:"description"`
+	Number           types.Int64       `tfsdk:"number"`
+	SubnetID         types.String      `tfsdk:"subnet_id"`
+	SubnetCIDR       types.String      `tfsdk:"subnet_cidr"`
+	VPCID            types.String      `tfsdk:"vpc_id"`
+	VPCPrimaryCIDR   types.String      `tfsdk:"vpc_primary_cidr"`
+	VPCCIDRs         []string          `tfsdk:"vpc_cidrs"`
+	Addresses        []string          `tfsdk:"addresses"`
+	Prefixes         []string          `tfsdk:"prefixes"`
+	SecurityGroups   []string          `tfsdk:"security_groups"`
+	Tags             map[string]string `tfsdk:"tags"`
+}
+
+type ciliumNodeAzureStatusModel struct {
+	Interfaces []azureInterfaceModel `tfsdk:"interfaces"`
+}
+
+type azureInterfaceModel struct {
+	ID            types.String        `tfsdk:"id"`
+	Name          types.String        `tfsdk:"name"`
+	MAC           types.String        `tfsdk:"mac"`
+	State         types.String        `tfsdk:"state"`
+	Addresses     []azureAddressModel `tfsdk:"addresses"`
+	SecurityGroup types.String        `tfsdk:"security_group"`
+	Gateway       types.String        `tfsdk:"gateway"`
+	CIDR          types.String        `tfsdk:"cidr"`
+}
+
+type azureAddressModel struct {
+	IP     types.String `tfsdk:"ip"`
+	Subnet types.String `tfsdk:"subnet"`
+	State  types.String `tfsdk:"state"`
+}
+
+type ciliumNodeAlibabaCloudStatusModel struct {
+	ENIs map[string]alibabaCloudENIModel `tfsdk:"enis"`
+}
+
+type alibabaCloudENIModel struct {
+	NetworkInterfaceID types.String      `tfsdk:"network_interface_id"`
+	MACAddress         types.String      `tfsdk:"mac_address"`
+	Type               types.String      `tfsdk:"type"`
+	InstanceID         types.String      `tfsdk:"instance_id"`
+	SecurityGroupIDs   []string          `tfsdk:"security_group_ids"`
+	VPCID              types.String      `tfsdk:"vpc_id"`
+	VPCCIDR            types.String      `tfsdk:"vpc_cidr"`
+	ZoneID             types.String      `tfsdk:"zone_id"`
+	VSwitchID          types.String      `tfsdk:"vswitch_id"`
+	VSwitchCIDR        types.String      `tfsdk:"vswitch_cidr"`
+	PrimaryIPAddress   types.String      `tfsdk:"primary_ip_address"`
+	PrivateIPAddresses []string          `tfsdk:"private_ip_addresses"`
+	Tags               map[string]string `tfsdk:"tags"`
+}
+
+// flattenCiliumNodeModel converts a CiliumNode into the model shared by the
+// node data sources. Nested objects are always set so that their attributes
+// can be referenced without checking for null first.
+func flattenCiliumNodeModel(cn *ciliumv2.CiliumNode) ciliumNodeModel {
+	spec, status := &cn.Spec, &cn.Status
+	m := ciliumNodeModel{
+		ApiVersion: types.StringValue(ciliumv2.SchemeGroupVersion.String()),
+		Kind:       types.StringValue(ciliumv2.CNKindDefinition),
+		Metadata:   flattenDataSourceObjectMeta(cn.ObjectMeta),
+		Spec: &ciliumNodeFullSpecModel{
+			InstanceID:   stringValueOrNull(spec.InstanceID),
+			NodeIdentity: int64ValueOrNull(int(spec.NodeIdentity)),
+			Health: &addressPairModel{
+				IPv4: stringValueOrNull(spec.HealthAddressing.IPv4),
+				IPv6: stringValueOrNull(spec.HealthAddressing.IPv6),
+			},
+			Ingress: &addressPairModel{
+				IPv4: stringValueOrNull(spec.IngressAddressing.IPV4),
+				IPv6: stringValueOrNull(spec.IngressAddressing.IPV6),
+			},
+			Encryption: &ciliumNodeEncryptionModel{
+				Key: int64ValueOrNull(spec.Encryption.Key),
+			},
+			IPAM: &ciliumNodeIPAMSpecModel{
+				Pool:                       flattenAllocationMap(spec.IPAM.Pool),
+				PodCIDRs:                   nilIfEmpty(spec.IPAM.PodCIDRs),
+				MinAllocate:                int64ValueOrNull(spec.IPAM.MinAllocate),
+				MaxAllocate:                int64ValueOrNull(spec.IPAM.MaxAllocate),
+				PreAllocate:                int64ValueOrNull(spec.IPAM.PreAllocate),
+				MaxAboveWatermark:          int64ValueOrNull(spec.IPAM.MaxAboveWatermark),
+				PodCIDRAllocationThreshold: int64ValueOrNull(spec.IPAM.PodCIDRAllocationThreshold),
+				PodCIDRReleaseThreshold:    int64ValueOrNull(spec.IPAM.PodCIDRReleaseThreshold),
+			},
+			ENI: flattenENISpec(&spec.ENI),
+			Azure: &ciliumNodeAzureSpecModel{
+				InterfaceName: stringValueOrNull(spec.Azure.InterfaceName),
+			},
+			AlibabaCloud: &ciliumNodeAlibabaCloudSpecModel{
+				InstanceType:      stringValueOrNull(spec.AlibabaCloud.InstanceType),
+				AvailabilityZone:  stringValueOrNull(spec.AlibabaCloud.AvailabilityZone),
+				VPCID:             stringValueOrNull(spec.AlibabaCloud.VPCID),
+				CIDRBlock:         stringValueOrNull(spec.AlibabaCloud.CIDRBlock),
+				VSwitches:         nilIfEmpty(spec.AlibabaCloud.VSwitches),
+				VSwitchTags:       nilIfEmptyMap(spec.AlibabaCloud.VSwitchTags),
+				SecurityGroups:    nilIfEmpty(spec.AlibabaCloud.SecurityGroups),
+				SecurityGroupTags: nilIfEmptyMap(spec.AlibabaCloud.SecurityGroupTags),
+			},
+		},
+		Status: &ciliumNodeStatusModel{
+			IPAM: &ciliumNodeIPAMStatusModel{
+				Used:          flattenAllocationMap(status.IPAM.Used),
+				OperatorError: stringValueOrNull(status.IPAM.OperatorStatus.Error),
+			},
+			ENI:          &ciliumNodeENIStatusModel{},
+			Azure:        &ciliumNodeAzureStatusModel{},
+			AlibabaCloud: &ciliumNodeAlibabaCloudStatusModel{},
+		},
+	}
+
+	for _, address := range spec.Addresses {
+		m.Spec.Addresses = append(m.Spec.Addresses, nodeAddressModel{
+			Type: types.StringValue(string(address.Type)),
+			IP:   types.StringValue(address.IP),
+		})
+	}
+
+	if len(status.IPAM.PodCIDRs) > 0 {
+		m.Status.IPAM.PodCIDRs = make(map[string]string, len(status.IPAM.PodCIDRs))
+		for cidr, entry := range status.IPAM.PodCIDRs {
+			m.Status.IPAM.PodCIDRs[cidr] = string(entry.Status)
+		}
+	}
+	if len(status.IPAM.ReleaseIPs) > 0 {
+		m.Status.IPAM.ReleaseIPs = make(map[string]string, len(status.IPAM.ReleaseIPs))
+		for ip, releaseStatus := range status.IPAM.ReleaseIPs {
+			m.Status.IPAM.ReleaseIPs[ip] = string(releaseStatus)
+		}
+	}
+
+	if len(status.ENI.ENIs) > 0 {
+		m.Status.ENI.ENIs = make(map[string]eniModel, len(status.ENI.ENIs))
+		for id, eni := range status.ENI.ENIs {
+			m.Status.ENI.ENIs[id] = eniModel{
+				ID:               stringValueOrNull(eni.ID),
+				IP:               stringValueOrNull(eni.IP),
+				MAC:              stringValueOrNull(eni.MAC),
+				AvailabilityZone: stringValueOrNull(eni.AvailabilityZone),
+				Description:      stringValueOrNull(eni.Description),
+				Number:           types.Int64Value(int64(eni.Number)),
+				SubnetID:         stringValueOrNull(eni.Subnet.ID),
+				SubnetCIDR:       stringValueOrNull(eni.Subnet.CIDR),
+				VPCID:            stringValueOrNull(eni.VPC.ID),
+				VPCPrimaryCIDR:   stringValueOrNull(eni.VPC.PrimaryCIDR),
+				VPCCIDRs:         nilIfEmpty(eni.VPC.CIDRs),
+				Addresses:        nilIfEmpty(eni.Addresses),
+				Prefixes:         nilIfEmpty(eni.Prefixes),
+				SecurityGroups:   nilIfEmpty(eni.SecurityGroups),
+				Tags:             nilIfEmptyMap(eni.Tags),
+			}
+		}
+	}
+
+	for _, iface := range status.Azure.Interfaces {
+		azureInterface := azureInterfaceModel{
+			ID:            stringValueOrNull(iface.ID),
+			Name:          stringValueOrNull(iface.Name),
+			MAC:           stringValueOrNull(iface.MAC),
+			State:         stringValueOrNull(iface.State),
+			SecurityGroup: stringValueOrNull(iface.SecurityGroup),
+			Gateway:       stringValueOrNull(iface.Gateway),
+			CIDR:          stringValueOrNull(iface.CIDR),
+		}
+		for _, address := range iface.Addresses {
+			azureInterface.Addresses = append(azureInterface.Addresses, azureAddressModel{
+				IP:     stringValueOrNull(address.IP),
+				Subnet: stringValueOrNull(address.Subnet),
+				State:  stringValueOrNull(address.State),
+			})
+		}
+		m.Status.Azure.Interfaces = append(m.Status.Azure.Interfaces, azureInterface)
+	}
+
+	if len(status.AlibabaCloud.ENIs) > 0 {
+		m.Status.AlibabaCloud.ENIs = make(map[string]alibabaCloudENIModel, len(status.AlibabaCloud.ENIs))
+		for id, eni := range status.AlibabaCloud.ENIs {
+			alibabaCloudENI := alibabaCloudENIModel{
+				NetworkInterfaceID: stringValueOrNull(eni.NetworkInterfaceID),
+				MACAddress:         stringValueOrNull(eni.MACAddress),
+				Type:               stringValueOrNull(eni.Type),
+				InstanceID:         stringValueOrNull(eni.InstanceID),
+				SecurityGroupIDs:   nilIfEmpty(eni.SecurityGroupIDs),
+				VPCID:              stringValueOrNull(eni.VPC.VPCID),
+				VPCCIDR:            stringValueOrNull(eni.VPC.CIDRBlock),
+				ZoneID:             stringValueOrNull(eni.ZoneID),
+				VSwitchID:          stringValueOrNull(eni.VSwitch.VSwitchID),
+				VSwitchCIDR:        stringValueOrNull(eni.VSwitch.CIDRBlock),
+				PrimaryIPAddress:   stringValueOrNull(eni.PrimaryIPAddress),
+				Tags:               nilIfEmptyMap(eni.Tags),
+			}
+			for _, ip := range eni.PrivateIPSets {
+				if !ip.Primary {
+					alibabaCloudENI.PrivateIPAddresses = append(alibabaCloudENI.PrivateIPAddresses, ip.PrivateIpAddress)
+				}
+			}
+			m.Status.AlibabaCloud.ENIs[id] = alibabaCloudENI
+		}
+	}
+
+	return m
+}
+
+func flattenENISpec(spec *eniTypes.ENISpec) *ciliumNodeENISpecModel {
+	m := &ciliumNodeENISpecModel{
+		InstanceID:              stringValueOrNull(spec.InstanceID),
+		InstanceType:            stringValueOrNull(spec.InstanceType),
+		MinAllocate:             int64ValueOrNull(spec.MinAllocate),
+		PreAllocate:             int64ValueOrNull(spec.PreAllocate),
+		MaxAboveWatermark:       int64ValueOrNull(spec.MaxAboveWatermark),
+		SecurityGroups:          nilIfEmpty(spec.SecurityGroups),
+		SecurityGroupTags:       nilIfEmptyMap(spec.SecurityGroupTags),
+		SubnetIDs:               nilIfEmpty(spec.SubnetIDs),
+		SubnetTags:              nilIfEmptyMap(spec.SubnetTags),
+		NodeSubnetID:            stringValueOrNull(spec.NodeSubnetID),
+		VpcID:                   stringValueOrNull(spec.VpcID),
+		AvailabilityZone:        stringValueOrNull(spec.AvailabilityZone),
+		ExcludeInterfaceTags:    nilIfEmptyMap(spec.ExcludeInterfaceTags),
+		DeleteOnTermination:     boolPointerValue(spec.DeleteOnTermination),
+		UsePrimaryAddress:       boolPointerValue(spec.UsePrimaryAddress),
+		DisablePrefixDelegation: boolPointerValue(spec.DisablePrefixDelegation),
+	}
+	if spec.FirstInterfaceIndex != nil {
+		m.FirstInterfaceIndex = types.Int64Value(int64(*spec.FirstInterfaceIndex))
+	}
+	return m
 }
 
-type metadataModel struct {
-	annotations map[string]string `tfsdk:"annotations"`
-	labels      map[string]string `tfsdk:"labels"`
-	name        types.String      `tfsdk:"name"`
-	namespace   types.String      `tfsdk:"namespace"`
+func flattenAllocationMap(allocations ipamTypes.AllocationMap) map[string]allocationIPModel {
+	if len(allocations) == 0 {
+		return nil
+	}
+	m := make(map[string]allocationIPModel, len(allocations))
+	for ip, allocation := range allocations {
+		m[ip] = allocationIPModel{
+			Owner:    stringValueOrNull(allocation.Owner),
+			Resource: stringValueOrNull(allocation.Resource),
+		}
+	}
+	return m
 }
 
-type specModel struct {
-	instanceID types.String `tfsdk:"instance-id"`
+// boolPointerValue maps a nil pointer to a null value.
+func boolPointerValue(value *bool) types.Bool {
+	if value == nil {
+		return types.BoolNull()
+	}
+	return types.BoolValue(*value)
 }
@@ -1,11 +1,165 @@
 package cilium
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eniTypes "github.com/cilium/cilium/pkg/aws/eni/types"
+	ipamTypes "github.com/cilium/cilium/pkg/ipam/types"
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/node/addressing"
 )
 
+func TestCiliumNodeDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+
+	firstInterfaceIndex := 1
+	node := &ciliumv2.CiliumNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"role": "worker"}},
+		Spec: ciliumv2.NodeSpec{
+			InstanceID: "i-0123456789",
+			Addresses: []ciliumv2.NodeAddress{
+				{Type: addressing.NodeInternalIP, IP: "10.0.0.10"},
+				{Type: addressing.NodeCiliumInternalIP, IP: "10.244.1.1"},
+				{Type: addressing.NodeExternalIP, IP: "203.0.113.10"},
+			},
+			HealthAddressing: ciliumv2.HealthAddressingSpec{IPv4: "10.244.1.2"},
+			Encryption:       ciliumv2.EncryptionSpec{Key: 3},
+			IPAM: ipamTypes.IPAMSpec{
+				PodCIDRs: []string{"10.244.1.0/24"},
+				Pool: ipamTypes.AllocationMap{
+					"10.244.1.20": {Resource: "eni-1"},
+				},
+			},
+			ENI: eniTypes.ENISpec{
+				InstanceType:        "m5.large",
+				FirstInterfaceIndex: &firstInterfaceIndex,
+				SubnetTags:          map[string]string{"tier": "pods"},
+			},
+		},
+		Status: ciliumv2.NodeStatus{
+			IPAM: ipamTypes.IPAMStatus{
+				Used: ipamTypes.AllocationMap{
+					"10.244.1.21": {Owner: "default/web", Resource: "eni-1"},
+				},
+				PodCIDRs: ipamTypes.PodCIDRMap{
+					"10.244.1.0/24": {Status: ipamTypes.PodCIDRStatusInUse},
+				},
+			},
+			ENI: eniTypes.ENIStatus{ENIs: map[string]eniTypes.ENI{
+				"eni-1": {ID: "eni-1", IP: "10.0.0.10", Number: 0, Addresses: []string{"10.244.1.20", "10.244.1.21"}},
+			}},
+		},
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(node)}
+
+	state, diags := testReadDataSource(t, NewCiliumNodeDataSource(), client, nil)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumNodeDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(model.CiliumNodes) != 1 {
+		t.Fatalf("got %d nodes, want 1", len(model.CiliumNodes))
+	}
+	cn := model.CiliumNodes[0]
+
+	if cn.Kind.ValueString() != "CiliumNode" || cn.Metadata.Name.ValueString() != "worker-1" || cn.Metadata.Labels["role"] != "worker" {
+		t.Errorf("unexpected node %s %+v", cn.Kind, cn.Metadata)
+	}
+	addresses := map[string]string{}
+	for _, address := range cn.Spec.Addresses {
+		addresses[address.Type.ValueString()] = address.IP.ValueString()
+	}
+	if addresses["InternalIP"] != "10.0.0.10" || addresses["CiliumInternalIP"] != "10.244.1.1" || addresses["ExternalIP"] != "203.0.113.10" {
+		t.Errorf("unexpected addresses %v", addresses)
+	}
+	if cn.Spec.Encryption.Key.ValueInt64() != 3 || cn.Spec.Health.IPv4.ValueString() != "10.244.1.2" || !cn.Spec.Health.IPv6.IsNull() {
+		t.Errorf("unexpected encryption %+v or health %+v", cn.Spec.Encryption, cn.Spec.Health)
+	}
+	if cn.Spec.IPAM.Pool["10.244.1.20"].Resource.ValueString() != "eni-1" || len(cn.Spec.IPAM.PodCIDRs) != 1 {
+		t.Errorf("unexpected ipam %+v", cn.Spec.IPAM)
+	}
+	if cn.Spec.ENI.FirstInterfaceIndex.ValueInt64() != 1 || cn.Spec.ENI.SubnetTags["tier"] != "pods" || !cn.Spec.ENI.DeleteOnTermination.IsNull() {
+		t.Errorf("unexpected eni spec %+v", cn.Spec.ENI)
+	}
+	if cn.Spec.Azure == nil || !cn.Spec.Azure.InterfaceName.IsNull() {
+		t.Errorf("azure spec = %+v, want an object with null attributes", cn.Spec.Azure)
+	}
+	if cn.Status.IPAM.Used["10.244.1.21"].Owner.ValueString() != "default/web" || cn.Status.IPAM.PodCIDRs["10.244.1.0/24"] != "in-use" {
+		t.Errorf("unexpected ipam status %+v", cn.Status.IPAM)
+	}
+	if eni := cn.Status.ENI.ENIs["eni-1"]; eni.Number.ValueInt64() != 0 || len(eni.Addresses) != 2 {
+		t.Errorf("unexpected eni status %+v", eni)
+	}
+}
+
+func TestCiliumNodeDataSourceSelectors(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(
+		&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "control-plane", Labels: map[string]string{"role": "control-plane"}}},
+		&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "worker-1", Labels: map[string]string{"role": "worker"}}},
+		&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "worker-2", Labels: map[string]string{"role": "worker"}}},
+	)}
+
+	state, diags := testReadDataSource(t, NewCiliumNodeDataSource(), client, map[string]tftypes.Value{
+		"label_selector": tftypes.NewValue(tftypes.String, "role=worker"),
+		"page_size":      tftypes.NewValue(tftypes.Number, 1),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumNodeDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	var names []string
+	for _, cn := range model.CiliumNodes {
+		names = append(names, cn.Metadata.Name.ValueString())
+	}
+	if strings.Join(names, ",") != "worker-1,worker-2" {
+		t.Errorf("got nodes %v, want worker-1 and worker-2", names)
+	}
+	if model.PageSize.ValueInt64() != 1 || model.LabelSelector.ValueString() != "role=worker" {
+		t.Errorf("configured attributes were not kept: %+v", model)
+	}
+}
+
+func TestCiliumNodeDataSourceValidatePageSize(t *testing.T) {
+	ctx := context.Background()
+	d := NewCiliumNodeDataSource().(datasource.DataSourceWithValidateConfig)
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	for pageSize, wantError := range map[int]bool{0: true, -1: true, 1: false, 500: false} {
+		attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+		for name, attributeType := range objectType.AttributeTypes {
+			attributes[name] = tftypes.NewValue(attributeType, nil)
+		}
+		attributes["page_size"] = tftypes.NewValue(tftypes.Number, pageSize)
+
+		var resp datasource.ValidateConfigResponse
+		d.ValidateConfig(ctx, datasource.ValidateConfigRequest{
+			Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)},
+		}, &resp)
+		if resp.Diagnostics.HasError() != wantError {
+			t.Errorf("page_size = %d: got diagnostics %v, want error %t", pageSize, resp.Diagnostics, wantError)
+		}
+	}
+}
+
 func TestAccCoffeesDataSource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
@@ -15,9 +169,10 @@ func TestAccCoffeesDataSource(t *testing.T) {
 				Config: providerConfig + `data "cilium_ciliumnodes" "test" {}`,
 				Check: resource.ComposeAggregateTestCheckFunc(
 					// Verify number of ciliumnodes returned
-					resource.TestCheckResourceAttr("data.cilium_ciliumnodes.test", "ciliumnode.#", "3"),
-					// Verify placeholder id attribute
-					resource.TestCheckResourceAttr("data.cilium_ciliumnodes.test", "id", "placeholder"),
+					resource.TestCheckResourceAttr("data.cilium_ciliumnodes.test", "ciliumnodes.#", "3"),
+					resource.TestCheckResourceAttrSet("data.cilium_ciliumnodes.test", "ciliumnodes.0.spec.addresses.#"),
+					resource.TestCheckResourceAttrSet("data.cilium_ciliumnodes.test", "id"),
+					resource.TestCheckResourceAttrSet("data.cilium_ciliumnodes.test", "content_hash"),
 				),
 			},
 		},
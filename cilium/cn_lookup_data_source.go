@@ -0,0 +1,110 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ciliumNodeLookupDataSource{}
+	_ datasource.DataSourceWithConfigure = &ciliumNodeLookupDataSource{}
+)
+
+// NewCiliumNodeLookupDataSource is a helper function to simplify the provider implementation.
+func NewCiliumNodeLookupDataSource() datasource.DataSource {
+	return &ciliumNodeLookupDataSource{}
+}
+
+// ciliumNodeLookupDataSource reads a single CiliumNode by name.
+type ciliumNodeLookupDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumNodeLookupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_node"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumNodeLookupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes := ciliumNodeAttributes()
+	attributes["id"] = schema.StringAttribute{
+		Description: "Identifier of the node, its name.",
+		Computed:    true,
+	}
+	attributes["name"] = schema.StringAttribute{
+		Description: "Name of the CiliumNode.",
+		Required:    true,
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads a CiliumNode by name.",
+		Attributes:  attributes,
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumNodeLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumNodeLookupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := state.Name.ValueString()
+
+	cn, err := d.client.GetCiliumNode(ctx, name)
+	if apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("CiliumNode Not Found", fmt.Sprintf("No CiliumNode named %q exists in the cluster.", name))
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read CiliumNode", kubernetesErrorDetail(err))
+		return
+	}
+
+	node := flattenCiliumNodeModel(cn)
+	state.ID = types.StringValue(cn.Name)
+	state.ApiVersion = node.ApiVersion
+	state.Kind = node.Kind
+	state.Metadata = node.Metadata
+	state.Spec = node.Spec
+	state.Status = node.Status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumNodeLookupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumNodeLookupDataSourceModel maps the data source schema data, the
+// attributes of ciliumNodeModel plus the lookup key.
+type ciliumNodeLookupDataSourceModel struct {
+	ID         types.String               `tfsdk:"id"`
+	Name       types.String               `tfsdk:"name"`
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Spec       *ciliumNodeFullSpecModel   `tfsdk:"spec"`
+	Status     *ciliumNodeStatusModel     `tfsdk:"status"`
+}
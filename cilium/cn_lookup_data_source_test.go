@@ -0,0 +1,71 @@
+package cilium
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/node/addressing"
+)
+
+func TestCiliumNodeLookupDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&ciliumv2.CiliumNode{
+		ObjectMeta: metav1.ObjectMeta{Name: "worker-1"},
+		Spec: ciliumv2.NodeSpec{
+			Addresses: []ciliumv2.NodeAddress{{Type: addressing.NodeInternalIP, IP: "10.0.0.10"}},
+		},
+	})}
+
+	state, diags := testReadDataSource(t, NewCiliumNodeLookupDataSource(), client, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "worker-1"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumNodeLookupDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "worker-1" || model.Kind.ValueString() != "CiliumNode" {
+		t.Errorf("unexpected id %s or kind %s", model.ID, model.Kind)
+	}
+	if len(model.Spec.Addresses) != 1 || model.Spec.Addresses[0].IP.ValueString() != "10.0.0.10" {
+		t.Errorf("unexpected addresses %+v", model.Spec.Addresses)
+	}
+
+	_, diags = testReadDataSource(t, NewCiliumNodeLookupDataSource(), client, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "worker-2"),
+	})
+	if !diags.HasError() || diags[0].Summary() != "CiliumNode Not Found" || !strings.Contains(diags[0].Detail(), `"worker-2"`) {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestAccCiliumNodeLookupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+data "cilium_ciliumnodes" "all" {}
+
+data "cilium_node" "test" {
+  name = data.cilium_ciliumnodes.all.ciliumnodes[0].metadata.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.cilium_node.test", "id", "data.cilium_ciliumnodes.all", "ciliumnodes.0.metadata.name"),
+					resource.TestCheckResourceAttrSet("data.cilium_node.test", "spec.addresses.#"),
+				),
+			},
+		},
+	})
+}
@@ -2,16 +2,27 @@ package cilium
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	ipamTypes "github.com/cilium/cilium/pkg/ipam/types"
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource = &ciliumNodeResource{}
+	_ resource.Resource                = &ciliumNodeResource{}
+	_ resource.ResourceWithConfigure   = &ciliumNodeResource{}
+	_ resource.ResourceWithImportState = &ciliumNodeResource{}
 )
 
 // NewCiliumNodeResource is a helper function to simplify the provider implementation.
@@ -20,8 +31,44 @@ func NewCiliumNodeResource() resource.Resource {
 }
 
 // ciliumNodeResourceModel maps the resource schema data.
+//
+// CiliumNode objects are shared with the Cilium agent and operator, so the
+// resource only manages the labels, annotations and spec fields which are
+// set in the configuration and leaves everything else untouched.
 type ciliumNodeResourceModel struct {
-	ID types.String `tfsdk:"id"`
+	ID           types.String            `tfsdk:"id"`
+	Metadata     *clusterObjectMetaModel `tfsdk:"metadata"`
+	Spec         *ciliumNodeSpecModel    `tfsdk:"spec"`
+	ForceDestroy types.Bool              `tfsdk:"force_destroy"`
+}
+
+type ciliumNodeSpecModel struct {
+	IPAM       *ciliumNodeIPAMModel       `tfsdk:"ipam"`
+	Encryption *ciliumNodeEncryptionModel `tfsdk:"encryption"`
+	Health     *ciliumNodeHealthModel     `tfsdk:"health"`
+}
+
+type ciliumNodeIPAMModel struct {
+	Pool              map[string]allocationIPModel `tfsdk:"pool"`
+	PodCIDRs          []string                     `tfsdk:"pod_cidrs"`
+	MinAllocate       types.Int64                  `tfsdk:"min_allocate"`
+	MaxAllocate       types.Int64                  `tfsdk:"max_allocate"`
+	PreAllocate       types.Int64                  `tfsdk:"pre_allocate"`
+	MaxAboveWatermark types.Int64                  `tfsdk:"max_above_watermark"`
+}
+
+type allocationIPModel struct {
+	Owner    types.String `tfsdk:"owner"`
+	Resource types.String `tfsdk:"resource"`
+}
+
+type ciliumNodeEncryptionModel struct {
+	Key types.Int64 `tfsdk:"key"`
+}
+
+type ciliumNodeHealthModel struct {
+	IPv4 types.String `tfsdk:"ipv4"`
+	IPv6 types.String `tfsdk:"ipv6"`
 }
 
 // ciliumNodeResource is the resource implementation.
@@ -36,7 +83,15 @@ func (r *ciliumNodeResource) Configure(_ context.Context, req resource.Configure
 	if req.ProviderData == nil {
 		return
 	}
-	r.client = req.ProviderData.(*CiliumClient)
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
 }
 
 // Metadata returns the resource type name.
@@ -46,27 +101,100 @@ func (r *ciliumNodeResource) Metadata(_ context.Context, req resource.MetadataRe
 
 // Schema defines the schema for the resource.
 func (r *ciliumNodeResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	metadata := metadataAttribute(false)
+	metadata.Description = "Metadata of the CiliumNode. Only the labels and annotations set here are managed; " +
+		"those added by the agent or operator are left untouched."
+
 	resp.Schema = schema.Schema{
+		Description: "Manages the spec fields of a CiliumNode. Fields which are not configured are left to the Cilium agent and operator. " +
+			"A CiliumNode already registered by the agent is adopted on create.",
 		Attributes: map[string]schema.Attribute{
-			"ciliumnodes": schema.ListNestedAttribute{
-				Computed: true,
-				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"apiversion": schema.StringAttribute{
-							Computed: true,
-						},
-						"kind": schema.StringAttribute{
-							Computed: true,
+			"id": schema.StringAttribute{
+				Description: "Identifier of the node, its name.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"metadata": metadata,
+			"spec": schema.SingleNestedAttribute{
+				Description: "Managed fields of the CiliumNode spec.",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"ipam": schema.SingleNestedAttribute{
+						Description: "IPAM settings of the node.",
+						Optional:    true,
+						Attributes: map[string]schema.Attribute{
+							"pool": schema.MapNestedAttribute{
+								Description: "IPs available to the node, keyed by IP. Only the IPs set here are managed; those added by the operator are left untouched.",
+								Optional:    true,
+								NestedObject: schema.NestedAttributeObject{
+									Attributes: map[string]schema.Attribute{
+										"owner": schema.StringAttribute{
+											Description: "Owner of the IP once it is allocated.",
+											Optional:    true,
+										},
+										"resource": schema.StringAttribute{
+											Description: "Resource the IP is associated with, e.g. an ENI.",
+											Optional:    true,
+										},
+									},
+								},
+							},
+							"pod_cidrs": schema.ListAttribute{
+								Description: "CIDRs from which pod IPs are allocated.",
+								ElementType: types.StringType,
+								Optional:    true,
+							},
+							"min_allocate": schema.Int64Attribute{
+								Description: "Minimum number of IPs to allocate when the node is first bootstrapped.",
+								Optional:    true,
+							},
+							"max_allocate": schema.Int64Attribute{
+								Description: "Maximum number of IPs which can be allocated to the node.",
+								Optional:    true,
+							},
+							"pre_allocate": schema.Int64Attribute{
+								Description: "Number of IPs to keep available for immediate allocation.",
+								Optional:    true,
+							},
+							"max_above_watermark": schema.Int64Attribute{
+								Description: "Maximum number of addresses to allocate beyond the addresses needed to reach pre_allocate.",
+								Optional:    true,
+							},
 						},
-						"metadata": schema.ObjectAttribute{
-							Computed: true,
+					},
+					"encryption": schema.SingleNestedAttribute{
+						Description: "Encryption settings of the node.",
+						Optional:    true,
+						Attributes: map[string]schema.Attribute{
+							"key": schema.Int64Attribute{
+								Description: "Index of the key to use for encryption, 0 disables encryption.",
+								Optional:    true,
+							},
 						},
-						"spec": schema.ObjectAttribute{
-							Computed: true,
+					},
+					"health": schema.SingleNestedAttribute{
+						Description: "Addresses of the health endpoint of the node.",
+						Optional:    true,
+						Attributes: map[string]schema.Attribute{
+							"ipv4": schema.StringAttribute{
+								Description: "IPv4 address of the health endpoint.",
+								Optional:    true,
+							},
+							"ipv6": schema.StringAttribute{
+								Description: "IPv6 address of the health endpoint.",
+								Optional:    true,
+							},
 						},
 					},
 				},
 			},
+			"force_destroy": schema.BoolAttribute{
+				Description: "Delete the CiliumNode even if it is still owned by a running agent, i.e. the Kubernetes node of the same name exists. " +
+					"The agent recreates a deleted CiliumNode, so this is mostly useful to clean up after a node was removed from the cluster.",
+				Optional: true,
+			},
 		},
 	}
 }
@@ -75,65 +203,386 @@ func (r *ciliumNodeResource) Schema(_ context.Context, _ resource.SchemaRequest,
 func (r *ciliumNodeResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	// Retrieve values from plan
 	var plan ciliumNodeResourceModel
-
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Set state to fully populated data
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	node := &ciliumv2.CiliumNode{
+		ObjectMeta: plan.Metadata.objectMeta(),
+	}
+	applyCiliumNodeSpec(&node.Spec, plan.Spec, nil)
+
+	created, err := r.client.CreateCiliumNode(ctx, node)
+	if apierrors.IsAlreadyExists(err) {
+		// The agent registers the CiliumNode of its node when it starts, so
+		// the node usually exists already: take over the configured fields
+		// of the existing object and leave the others to the agent.
+		tflog.Info(ctx, "CiliumNode already exists, adopting it", map[string]interface{}{"name": node.Name})
+		created, err = r.client.UpdateCiliumNode(ctx, node.Name, func(node *ciliumv2.CiliumNode) {
+			applyCiliumNode(node, &plan, nil)
+		})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Create Cilium Node", kubernetesErrorDetail(err))
 		return
 	}
+	tflog.Debug(ctx, "Created CiliumNode", map[string]interface{}{"name": created.Name})
+
+	plan.ID = types.StringValue(created.Name)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	// Set state to fully populated data
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Read refreshes the Terraform state with the latest data.
 func (r *ciliumNodeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
 	// Get current state
 	var state ciliumNodeResourceModel
-	diags := req.State.Get(ctx, &state)
-	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Set refreshed state
-	diags = resp.State.Set(ctx, &state)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	node, err := r.client.GetCiliumNode(ctx, state.ID.ValueString())
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, "CiliumNode not found, removing it from the state", map[string]interface{}{"name": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Node", kubernetesErrorDetail(err))
 		return
 	}
+
+	// Set refreshed state
+	resp.Diagnostics.Append(resp.State.Set(ctx, refreshCiliumNode(&state, node))...)
 }
 
 // Update updates the resource and sets the updated Terraform state on success.
 // https://developer.hashicorp.com/terraform/tutorials/providers-plugin-framework/providers-plugin-framework-resource-update#implement-update-functionality
 func (r *ciliumNodeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	// Retrieve values from plan
-	var plan ciliumNodeResourceModel
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+	// Retrieve values from plan and state
+	var plan, state ciliumNodeResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	diags = resp.State.Set(ctx, plan)
-	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
+	updated, err := r.client.UpdateCiliumNode(ctx, plan.Metadata.Name.ValueString(), func(node *ciliumv2.CiliumNode) {
+		applyCiliumNode(node, &plan, &state)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Update Cilium Node", kubernetesErrorDetail(err))
 		return
 	}
+
+	plan.ID = types.StringValue(updated.Name)
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
 // Delete deletes the resource and removes the Terraform state on success.
 // https://developer.hashicorp.com/terraform/tutorials/providers-plugin-framework/providers-plugin-framework-resource-delete#implement-delete-functionality
 func (r *ciliumNodeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	// Retrieve values from state
+	var state ciliumNodeResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	name := state.ID.ValueString()
 
+	if !state.ForceDestroy.ValueBool() {
+		_, err := r.client.GetNode(ctx, name)
+		if err == nil {
+			resp.Diagnostics.AddError(
+				"Cilium Node Still in Use",
+				fmt.Sprintf("The Kubernetes node %q still exists, so the CiliumNode is owned by a running Cilium agent which would recreate it. "+
+					"Remove the node from the cluster first, or set force_destroy = true to delete the CiliumNode anyway.", name),
+			)
+			return
+		}
+		if !apierrors.IsNotFound(err) {
+			resp.Diagnostics.AddError("Unable to Read Kubernetes Node", kubernetesErrorDetail(err))
+			return
+		}
+	}
+
+	err := r.client.DeleteCiliumNode(ctx, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Delete Cilium Node", kubernetesErrorDetail(err))
+	}
 }
 
+// ImportState imports a node by name. The labels, annotations and spec of a
+// node are mostly written by the Cilium agent and operator, so the following
+// Read leaves them out of the state: only the fields set in the configuration
+// are taken over, by the next apply.
 func (r *ciliumNodeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	// Retrieve import ID and save to id attribute
+	if req.ID == "" || strings.Contains(req.ID, "/") {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("CiliumNodes are cluster scoped and imported by name, got %q.", req.ID),
+		)
+		return
+	}
 	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
 }
+
+// refreshCiliumNode returns the state of node restricted to the fields
+// managed in state. A state without metadata comes from an import, which
+// manages none of the fields of the node yet.
+func refreshCiliumNode(state *ciliumNodeResourceModel, node *ciliumv2.CiliumNode) ciliumNodeResourceModel {
+	live := ciliumNodeResourceModel{
+		ID:           types.StringValue(node.Name),
+		Metadata:     flattenClusterObjectMeta(node.ObjectMeta),
+		Spec:         flattenCiliumNodeSpec(&node.Spec),
+		ForceDestroy: state.ForceDestroy,
+	}
+	if state.Metadata == nil {
+		state = &ciliumNodeResourceModel{Metadata: &clusterObjectMetaModel{}}
+	}
+
+	live.Metadata.Labels = maskMap(live.Metadata.Labels, state.Metadata.Labels)
+	live.Metadata.Annotations = maskMap(live.Metadata.Annotations, state.Metadata.Annotations)
+	live.Spec = maskCiliumNodeSpec(live.Spec, state.Spec)
+	return live
+}
+
+// flattenCiliumNodeSpec converts the managed fields of a node spec into their
+// Terraform representation.
+func flattenCiliumNodeSpec(spec *ciliumv2.NodeSpec) *ciliumNodeSpecModel {
+	m := &ciliumNodeSpecModel{
+		IPAM: &ciliumNodeIPAMModel{
+			PodCIDRs:          nilIfEmpty(spec.IPAM.PodCIDRs),
+			MinAllocate:       int64ValueOrNull(spec.IPAM.MinAllocate),
+			MaxAllocate:       int64ValueOrNull(spec.IPAM.MaxAllocate),
+			PreAllocate:       int64ValueOrNull(spec.IPAM.PreAllocate),
+			MaxAboveWatermark: int64ValueOrNull(spec.IPAM.MaxAboveWatermark),
+		},
+		Encryption: &ciliumNodeEncryptionModel{
+			Key: int64ValueOrNull(spec.Encryption.Key),
+		},
+		Health: &ciliumNodeHealthModel{
+			IPv4: stringValueOrNull(spec.HealthAddressing.IPv4),
+			IPv6: stringValueOrNull(spec.HealthAddressing.IPv6),
+		},
+	}
+	m.IPAM.Pool = flattenAllocationMap(spec.IPAM.Pool)
+	return m
+}
+
+// maskCiliumNodeSpec keeps the fields of live which are set in managed.
+func maskCiliumNodeSpec(live, managed *ciliumNodeSpecModel) *ciliumNodeSpecModel {
+	if managed == nil {
+		return nil
+	}
+	m := &ciliumNodeSpecModel{}
+	if managed.IPAM != nil {
+		m.IPAM = &ciliumNodeIPAMModel{
+			MinAllocate:       maskInt64(live.IPAM.MinAllocate, managed.IPAM.MinAllocate),
+			MaxAllocate:       maskInt64(live.IPAM.MaxAllocate, managed.IPAM.MaxAllocate),
+			PreAllocate:       maskInt64(live.IPAM.PreAllocate, managed.IPAM.PreAllocate),
+			MaxAboveWatermark: maskInt64(live.IPAM.MaxAboveWatermark, managed.IPAM.MaxAboveWatermark),
+		}
+		if managed.IPAM.Pool != nil {
+			m.IPAM.Pool = maskAllocations(live.IPAM.Pool, managed.IPAM.Pool)
+		}
+		if managed.IPAM.PodCIDRs != nil {
+			m.IPAM.PodCIDRs = live.IPAM.PodCIDRs
+			if m.IPAM.PodCIDRs == nil {
+				m.IPAM.PodCIDRs = []string{}
+			}
+		}
+	}
+	if managed.Encryption != nil {
+		m.Encryption = &ciliumNodeEncryptionModel{
+			Key: maskInt64(live.Encryption.Key, managed.Encryption.Key),
+		}
+	}
+	if managed.Health != nil {
+		m.Health = &ciliumNodeHealthModel{
+			IPv4: maskString(live.Health.IPv4, managed.Health.IPv4),
+			IPv6: maskString(live.Health.IPv6, managed.Health.IPv6),
+		}
+	}
+	return m
+}
+
+// applyCiliumNode sets the labels, annotations and spec fields of node
+// configured in plan and clears those which were managed in state but are no
+// longer configured. A nil state manages nothing, so no field is cleared.
+func applyCiliumNode(node *ciliumv2.CiliumNode, plan, state *ciliumNodeResourceModel) {
+	var stateMetadata clusterObjectMetaModel
+	var stateSpec *ciliumNodeSpecModel
+	if state != nil && state.Metadata != nil {
+		stateMetadata, stateSpec = *state.Metadata, state.Spec
+	}
+	node.Labels = applyManagedMap(node.Labels, plan.Metadata.Labels, stateMetadata.Labels)
+	node.Annotations = applyManagedMap(node.Annotations, plan.Metadata.Annotations, stateMetadata.Annotations)
+	applyCiliumNodeSpec(&node.Spec, plan.Spec, stateSpec)
+}
+
+// applyCiliumNodeSpec sets the fields of spec configured in plan and clears
+// the fields which were managed in state but are no longer configured.
+func applyCiliumNodeSpec(spec *ciliumv2.NodeSpec, plan, state *ciliumNodeSpecModel) {
+	planIPAM, stateIPAM := plan.ipam(), state.ipam()
+	spec.IPAM.Pool = applyManagedAllocations(spec.IPAM.Pool, planIPAM.Pool, stateIPAM.Pool)
+	switch {
+	case planIPAM.PodCIDRs != nil:
+		spec.IPAM.PodCIDRs = planIPAM.PodCIDRs
+	case stateIPAM.PodCIDRs != nil:
+		spec.IPAM.PodCIDRs = nil
+	}
+	applyInt(&spec.IPAM.MinAllocate, planIPAM.MinAllocate, stateIPAM.MinAllocate)
+	applyInt(&spec.IPAM.MaxAllocate, planIPAM.MaxAllocate, stateIPAM.MaxAllocate)
+	applyInt(&spec.IPAM.PreAllocate, planIPAM.PreAllocate, stateIPAM.PreAllocate)
+	applyInt(&spec.IPAM.MaxAboveWatermark, planIPAM.MaxAboveWatermark, stateIPAM.MaxAboveWatermark)
+
+	applyInt(&spec.Encryption.Key, plan.encryption().Key, state.encryption().Key)
+
+	planHealth, stateHealth := plan.health(), state.health()
+	applyString(&spec.HealthAddressing.IPv4, planHealth.IPv4, stateHealth.IPv4)
+	applyString(&spec.HealthAddressing.IPv6, planHealth.IPv6, stateHealth.IPv6)
+}
+
+// ipam, encryption and health return the nested models of m, or empty models
+// whose attributes are all null.
+func (m *ciliumNodeSpecModel) ipam() ciliumNodeIPAMModel {
+	if m == nil || m.IPAM == nil {
+		return ciliumNodeIPAMModel{}
+	}
+	return *m.IPAM
+}
+
+func (m *ciliumNodeSpecModel) encryption() ciliumNodeEncryptionModel {
+	if m == nil || m.Encryption == nil {
+		return ciliumNodeEncryptionModel{}
+	}
+	return *m.Encryption
+}
+
+func (m *ciliumNodeSpecModel) health() ciliumNodeHealthModel {
+	if m == nil || m.Health == nil {
+		return ciliumNodeHealthModel{}
+	}
+	return *m.Health
+}
+
+// applyManagedMap sets the entries of plan in current and removes the
+// entries which were managed in state but are no longer configured.
+func applyManagedMap(current, plan, state map[string]string) map[string]string {
+	for k := range state {
+		if _, ok := plan[k]; !ok {
+			delete(current, k)
+		}
+	}
+	if len(plan) > 0 && current == nil {
+		current = make(map[string]string, len(plan))
+	}
+	for k, v := range plan {
+		current[k] = v
+	}
+	return current
+}
+
+// applyManagedAllocations sets the IPs of plan in the pool current and
+// removes the IPs which were managed in state but are no longer configured.
+// The IPs added by the operator are left untouched.
+func applyManagedAllocations(current ipamTypes.AllocationMap, plan, state map[string]allocationIPModel) ipamTypes.AllocationMap {
+	for ip := range state {
+		if _, ok := plan[ip]; !ok {
+			delete(current, ip)
+		}
+	}
+	if len(plan) > 0 && current == nil {
+		current = make(ipamTypes.AllocationMap, len(plan))
+	}
+	for ip, allocation := range plan {
+		current[ip] = ipamTypes.AllocationIP{
+			Owner:    allocation.Owner.ValueString(),
+			Resource: allocation.Resource.ValueString(),
+		}
+	}
+	return current
+}
+
+// maskAllocations keeps the IPs of live which are set in managed.
+func maskAllocations(live, managed map[string]allocationIPModel) map[string]allocationIPModel {
+	masked := make(map[string]allocationIPModel, len(managed))
+	for ip := range managed {
+		if allocation, ok := live[ip]; ok {
+			masked[ip] = allocation
+		}
+	}
+	return masked
+}
+
+// maskMap keeps the entries of live whose key is set in managed.
+func maskMap(live, managed map[string]string) map[string]string {
+	if managed == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(managed))
+	for k := range managed {
+		if v, ok := live[k]; ok {
+			masked[k] = v
+		}
+	}
+	return masked
+}
+
+// maskInt64 keeps live if managed is set. Zero values are omitted by the
+// API, so an unset live value is reported as zero.
+func maskInt64(live, managed types.Int64) types.Int64 {
+	switch {
+	case managed.IsNull():
+		return types.Int64Null()
+	case live.IsNull():
+		return types.Int64Value(0)
+	}
+	return live
+}
+
+// maskString keeps live if managed is set, reporting an unset live value as
+// the empty string.
+func maskString(live, managed types.String) types.String {
+	switch {
+	case managed.IsNull():
+		return types.StringNull()
+	case live.IsNull():
+		return types.StringValue("")
+	}
+	return live
+}
+
+func applyInt(dst *int, plan, state types.Int64) {
+	switch {
+	case !plan.IsNull():
+		*dst = int(plan.ValueInt64())
+	case !state.IsNull():
+		*dst = 0
+	}
+}
+
+func applyString(dst *string, plan, state types.String) {
+	switch {
+	case !plan.IsNull():
+		*dst = plan.ValueString()
+	case !state.IsNull():
+		*dst = ""
+	}
+}
+
+// int64ValueOrNull maps a zero value to null, matching the omitempty
+// encoding of the Cilium types.
+func int64ValueOrNull(value int) types.Int64 {
+	if value == 0 {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(value))
+}
@@ -1,11 +1,26 @@
 package cilium
 
 import (
-	"regexp"
+	"context"
+	"reflect"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	tfresource "github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	kuberesource "k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	ipamTypes "github.com/cilium/cilium/pkg/ipam/types"
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
 )
 
 func checkParsableQuantity(value string) error {
@@ -16,39 +31,214 @@ func checkParsableQuantity(value string) error {
 }
 
 func TestAccCiliumNodeResource(t *testing.T) {
-	nodeName := regexp.MustCompile(`^[a-z0-9]+(?:[-.]{1}[a-z0-9]+)*$`)
-	zeroOrMore := regexp.MustCompile(`^[0-9]+$`)
-	oneOrMore := regexp.MustCompile(`^[1-9][0-9]*$`)
-
-	resource.Test(t, resource.TestCase{
+	tfresource.Test(t, tfresource.TestCase{
 		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
-		Steps: []resource.TestStep{
+		Steps: []tfresource.TestStep{
 			// Create and Read testing
 			{
 				Config: providerConfig + `
-resource "cilium_ciliumnodes" "test" {
-items = [
-{
-	# TODO
-},
-]
+resource "cilium_ciliumNode" "test" {
+  metadata = {
+    name = "tf-acc-node"
+    labels = {
+      "tf-acc" = "true"
+    }
+  }
+  spec = {
+    ipam = {
+      pod_cidrs    = ["10.250.0.0/24"]
+      pre_allocate = 8
+    }
+    health = {
+      ipv4 = "10.250.0.10"
+    }
+  }
+}
+`,
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckResourceAttr("cilium_ciliumNode.test", "id", "tf-acc-node"),
+					tfresource.TestCheckResourceAttrSet("cilium_ciliumNode.test", "metadata.uid"),
+					tfresource.TestCheckResourceAttr("cilium_ciliumNode.test", "spec.ipam.pod_cidrs.0", "10.250.0.0/24"),
+					tfresource.TestCheckResourceAttr("cilium_ciliumNode.test", "spec.ipam.pre_allocate", "8"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_ciliumNode.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// The labels and spec are only managed from the next apply.
+				ImportStateVerifyIgnore: []string{"force_destroy", "metadata.labels", "spec"},
+			},
+			// Update and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_ciliumNode" "test" {
+  metadata = {
+    name = "tf-acc-node"
+  }
+  spec = {
+    ipam = {
+      pod_cidrs    = ["10.250.0.0/24"]
+      pre_allocate = 16
+    }
+    encryption = {
+      key = 3
+    }
+  }
 }
 `,
-				Check: resource.ComposeAggregateTestCheckFunc(
-					resource.TestMatchResourceAttr("data.cilium_ciliumnodes.test", "nodes.#", oneOrMore),
-					resource.TestMatchResourceAttr("data.cilium_ciliumnodes.test", "nodes.0.metadata.0.labels.%", zeroOrMore),
-					resource.TestCheckResourceAttrSet("data.cilium_ciliumnodes.test", "nodes.0.metadata.0.resource_version"),
-					resource.TestMatchResourceAttr("data.cilium_ciliumnodes.test", "nodes.0.metadata.0.name", nodeName),
-					resource.TestMatchResourceAttr("data.cilium_ciliumnodes.test", "nodes.0.spec.0.%", oneOrMore),
-					resource.TestCheckResourceAttrWith("data.cilium_ciliumnodes.test", "nodes.0.status.0.capacity.cpu", checkParsableQuantity),
-					resource.TestCheckResourceAttrWith("data.cilium_ciliumnodes.test", "nodes.0.status.0.capacity.memory", checkParsableQuantity),
-					resource.TestCheckResourceAttrSet("data.cilium_ciliumnodes.test", "nodes.0.status.0.node_info.0.architecture"),
+				Check: tfresource.ComposeAggregateTestCheckFunc(
+					tfresource.TestCheckNoResourceAttr("cilium_ciliumNode.test", "metadata.labels.tf-acc"),
+					tfresource.TestCheckNoResourceAttr("cilium_ciliumNode.test", "spec.health"),
+					tfresource.TestCheckResourceAttr("cilium_ciliumNode.test", "spec.ipam.pre_allocate", "16"),
+					tfresource.TestCheckResourceAttr("cilium_ciliumNode.test", "spec.encryption.key", "3"),
 				),
 			},
 		},
 	})
 }
 
+func TestRefreshCiliumNodeOnlyManagedFields(t *testing.T) {
+	node := &ciliumv2.CiliumNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-1",
+			UID:    "8e5c0a3b",
+			Labels: map[string]string{"kubernetes.io/os": "linux", "team": "one"},
+		},
+		Spec: ciliumv2.NodeSpec{
+			IPAM: ipamTypes.IPAMSpec{
+				PodCIDRs:    []string{"10.0.1.0/24"},
+				PreAllocate: 8,
+				MinAllocate: 4,
+			},
+			HealthAddressing: ciliumv2.HealthAddressingSpec{IPv4: "10.0.1.10"},
+		},
+	}
+
+	// An imported node has no state yet and none of its fields are managed.
+	imported := refreshCiliumNode(&ciliumNodeResourceModel{}, node)
+	if imported.Metadata.Name.ValueString() != "node-1" || imported.Metadata.Labels != nil || imported.Spec != nil {
+		t.Errorf("imported node has managed fields: %+v %+v", imported.Metadata, imported.Spec)
+	}
+
+	state := &ciliumNodeResourceModel{
+		ID:       types.StringValue("node-1"),
+		Metadata: &clusterObjectMetaModel{Name: types.StringValue("node-1"), Labels: map[string]string{"team": "two"}},
+		Spec: &ciliumNodeSpecModel{
+			IPAM:       &ciliumNodeIPAMModel{PreAllocate: types.Int64Value(16)},
+			Encryption: &ciliumNodeEncryptionModel{Key: types.Int64Value(0)},
+		},
+	}
+	want := ciliumNodeResourceModel{
+		ID:       types.StringValue("node-1"),
+		Metadata: &clusterObjectMetaModel{Name: types.StringValue("node-1"), Labels: map[string]string{"team": "one"}, UID: types.StringValue("8e5c0a3b")},
+		Spec: &ciliumNodeSpecModel{
+			IPAM:       &ciliumNodeIPAMModel{PreAllocate: types.Int64Value(8)},
+			Encryption: &ciliumNodeEncryptionModel{Key: types.Int64Value(0)},
+		},
+	}
+	if got := refreshCiliumNode(state, node); !reflect.DeepEqual(got, want) {
+		t.Errorf("refreshCiliumNode() =\n%+v %+v\nwant\n%+v %+v", got.Metadata, got.Spec.IPAM, want.Metadata, want.Spec.IPAM)
+	}
+}
+
+func TestApplyCiliumNodeSpec(t *testing.T) {
+	spec := ciliumv2.NodeSpec{
+		IPAM: ipamTypes.IPAMSpec{
+			PodCIDRs:    []string{"10.0.1.0/24"},
+			PreAllocate: 8,
+			MinAllocate: 4,
+		},
+		HealthAddressing: ciliumv2.HealthAddressingSpec{IPv4: "10.0.1.10"},
+	}
+	state := &ciliumNodeSpecModel{
+		IPAM:   &ciliumNodeIPAMModel{PreAllocate: types.Int64Value(8)},
+		Health: &ciliumNodeHealthModel{IPv4: types.StringValue("10.0.1.10")},
+	}
+	plan := &ciliumNodeSpecModel{
+		IPAM:       &ciliumNodeIPAMModel{MaxAboveWatermark: types.Int64Value(2)},
+		Encryption: &ciliumNodeEncryptionModel{Key: types.Int64Value(3)},
+	}
+
+	applyCiliumNodeSpec(&spec, plan, state)
+
+	want := ciliumv2.NodeSpec{
+		IPAM: ipamTypes.IPAMSpec{
+			PodCIDRs:          []string{"10.0.1.0/24"},
+			MinAllocate:       4,
+			MaxAboveWatermark: 2,
+		},
+		Encryption: ciliumv2.EncryptionSpec{Key: 3},
+	}
+	if !reflect.DeepEqual(spec, want) {
+		t.Errorf("applyCiliumNodeSpec() = %+v, want %+v", spec, want)
+	}
+}
+
+func TestApplyManagedMap(t *testing.T) {
+	current := map[string]string{"kubernetes.io/os": "linux", "team": "one", "tier": "edge"}
+	got := applyManagedMap(current, map[string]string{"team": "two"}, map[string]string{"team": "one", "tier": "edge"})
+	want := map[string]string{"kubernetes.io/os": "linux", "team": "two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("applyManagedMap() = %v, want %v", got, want)
+	}
+
+	if got := applyManagedMap(nil, map[string]string{"team": "one"}, nil); !reflect.DeepEqual(got, map[string]string{"team": "one"}) {
+		t.Errorf("applyManagedMap() on nil map = %v", got)
+	}
+}
+
+func TestCiliumNodeResourceDeleteOwnedByAgent(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name         string
+		nodeExists   bool
+		forceDestroy bool
+		wantDeleted  bool
+	}{
+		{name: "node still in the cluster", nodeExists: true},
+		{name: "node removed from the cluster", wantDeleted: true},
+		{name: "forced", nodeExists: true, forceDestroy: true, wantDeleted: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []runtime.Object
+			if tt.nodeExists {
+				nodes = append(nodes, &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}})
+			}
+			client := &CiliumClient{
+				Clientset:       k8sfake.NewSimpleClientset(nodes...),
+				CiliumClientset: ciliumfake.NewSimpleClientset(&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}),
+			}
+			r := &ciliumNodeResource{client: client}
+
+			var schemaResp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+			state := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			diags := state.Set(ctx, ciliumNodeResourceModel{
+				ID:           types.StringValue("node-1"),
+				Metadata:     &clusterObjectMetaModel{Name: types.StringValue("node-1")},
+				ForceDestroy: types.BoolValue(tt.forceDestroy),
+			})
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			resp := resource.DeleteResponse{State: state}
+			r.Delete(ctx, resource.DeleteRequest{State: state}, &resp)
+			if resp.Diagnostics.HasError() == tt.wantDeleted {
+				t.Errorf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			_, err := client.GetCiliumNode(ctx, "node-1")
+			if deleted := apierrors.IsNotFound(err); deleted != tt.wantDeleted {
+				t.Errorf("CiliumNode deleted = %v, want %v", deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
 func testAccKubernetesDataSourceNodesConfig_basic() string {
 	return `
 data "cilium_ciliumnodes" "res" {}
@@ -66,3 +256,122 @@ data "cilium_ciliumnodes" "res" {
 }
 `
 }
+
+func TestCiliumNodeResourceImport(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&ciliumv2.CiliumNode{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "node-1",
+			Labels:      map[string]string{"role": "worker"},
+			Annotations: map[string]string{lastAppliedConfigAnnotation: "{}"},
+		},
+		Spec: ciliumv2.NodeSpec{
+			IPAM: ipamTypes.IPAMSpec{
+				PodCIDRs:    []string{"10.0.1.0/24"},
+				PreAllocate: 8,
+			},
+			HealthAddressing: ciliumv2.HealthAddressingSpec{IPv4: "10.0.1.10"},
+		},
+	})}
+
+	state, diags := testImportResource(t, NewCiliumNodeResource(), client, "node-1")
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumNodeResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "node-1" || model.Metadata.Name.ValueString() != "node-1" || model.Metadata.UID.IsNull() {
+		t.Errorf("unexpected id %s or metadata %+v", model.ID, model.Metadata)
+	}
+	// The fields written by the agent must not be taken over, or the next
+	// apply would remove them.
+	if model.Metadata.Labels != nil || model.Metadata.Annotations != nil || model.Spec != nil {
+		t.Errorf("unexpected managed labels %v, annotations %v or spec %+v", model.Metadata.Labels, model.Metadata.Annotations, model.Spec)
+	}
+
+	_, diags = testImportResource(t, NewCiliumNodeResource(), client, "default/node-1")
+	if !diags.HasError() || diags[0].Summary() != "Invalid Import ID" {
+		t.Errorf("expected an invalid ID error, got %v", diags)
+	}
+}
+
+// TestCiliumNodeResourceKeepsAgentFields applies a partial configuration to a
+// node populated by the agent, after an import and by adopting it on create,
+// and checks that only the configured fields are written.
+func TestCiliumNodeResourceKeepsAgentFields(t *testing.T) {
+	ctx := context.Background()
+	agentNode := func() *ciliumv2.CiliumNode {
+		return &ciliumv2.CiliumNode{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "node-1",
+				Labels:      map[string]string{"kubernetes.io/os": "linux"},
+				Annotations: map[string]string{"network.cilium.io/ipv4-cilium-host": "10.0.1.1"},
+			},
+			Spec: ciliumv2.NodeSpec{
+				IPAM: ipamTypes.IPAMSpec{
+					Pool:        ipamTypes.AllocationMap{"10.0.1.20": {Resource: "eni-1"}},
+					PodCIDRs:    []string{"10.0.1.0/24"},
+					PreAllocate: 8,
+				},
+				Encryption:       ciliumv2.EncryptionSpec{Key: 1},
+				HealthAddressing: ciliumv2.HealthAddressingSpec{IPv4: "10.0.1.10"},
+			},
+		}
+	}
+	plan := ciliumNodeResourceModel{
+		ID:       types.StringUnknown(),
+		Metadata: &clusterObjectMetaModel{Name: types.StringValue("node-1"), Labels: map[string]string{"team": "web"}, UID: types.StringUnknown()},
+		Spec: &ciliumNodeSpecModel{IPAM: &ciliumNodeIPAMModel{
+			Pool:        map[string]allocationIPModel{"10.0.1.30": {Owner: types.StringNull(), Resource: types.StringValue("eni-2")}},
+			PreAllocate: types.Int64Value(16),
+		}},
+		ForceDestroy: types.BoolNull(),
+	}
+	want := agentNode()
+	want.Labels["team"] = "web"
+	want.Spec.IPAM.Pool["10.0.1.30"] = ipamTypes.AllocationIP{Resource: "eni-2"}
+	want.Spec.IPAM.PreAllocate = 16
+
+	for _, name := range []string{"import", "create"} {
+		t.Run(name, func(t *testing.T) {
+			client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(agentNode())}
+			r := &ciliumNodeResource{client: client}
+
+			var schemaResp resource.SchemaResponse
+			r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+			planned := tfsdk.Plan{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+			if diags := planned.Set(ctx, plan); diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			var diags diag.Diagnostics
+			if name == "import" {
+				var state tfsdk.State
+				state, diags = testImportResource(t, r, client, "node-1")
+				if diags.HasError() {
+					t.Fatal(diags)
+				}
+				resp := resource.UpdateResponse{State: state}
+				r.Update(ctx, resource.UpdateRequest{Plan: planned, State: state}, &resp)
+				diags = resp.Diagnostics
+			} else {
+				resp := resource.CreateResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: planned.Raw}}
+				r.Create(ctx, resource.CreateRequest{Plan: planned}, &resp)
+				diags = resp.Diagnostics
+			}
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			node, err := client.GetCiliumNode(ctx, "node-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(node.Labels, want.Labels) || !reflect.DeepEqual(node.Annotations, want.Annotations) || !reflect.DeepEqual(node.Spec, want.Spec) {
+				t.Errorf("unexpected node %+v %+v, want %+v %+v", node.ObjectMeta, node.Spec, want.ObjectMeta, want.Spec)
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package cilium
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
@@ -33,37 +37,129 @@ func (d *ciliumNetworkPolicyDataSource) Metadata(_ context.Context, req datasour
 
 // Schema defines the schema for the data source.
 func (d *ciliumNetworkPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	policy, err := ciliumNetworkPolicyAttributes()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Data Source Schema", err.Error())
+		return
+	}
+
 	resp.Schema = schema.Schema{
+		Description: "Lists CiliumNetworkPolicies.",
 		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the listed policies, which changes whenever a policy is added, removed or modified.",
+				Computed:    true,
+			},
+			"content_hash": schema.StringAttribute{
+				Description: "SHA-256 hash of the names, labels, annotations, rules and status of the listed policies.",
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace to list the policies of. Defaults to all namespaces.",
+				Optional:    true,
+			},
+			"label_selector": schema.StringAttribute{
+				Description: "Kubernetes label selector restricting the listed policies, e.g. `app=web,tier!=db`.",
+				Optional:    true,
+			},
+			"field_selector": schema.StringAttribute{
+				Description: "Kubernetes field selector restricting the listed policies, e.g. `metadata.name=allow-dns`.",
+				Optional:    true,
+			},
 			"ciliumnetworkpolicies": schema.ListNestedAttribute{
-				Computed: true,
+				Description: "The matching policies.",
+				Computed:    true,
 				NestedObject: schema.NestedAttributeObject{
-					Attributes: map[string]schema.Attribute{
-						"apiversion": schema.StringAttribute{
-							Computed: true,
-						},
-						"kind": schema.StringAttribute{
-							Computed: true,
-						},
-					},
+					Attributes: policy,
 				},
 			},
 		},
 	}
 }
 
+// ciliumNetworkPolicyAttributes returns the schema of a policy read by a
+// data source.
+func ciliumNetworkPolicyAttributes() (map[string]schema.Attribute, error) {
+	rule, err := computedAttributes(ruleAttributes())
+	if err != nil {
+		return nil, fmt.Errorf("spec: %w", err)
+	}
+	status, err := computedAttribute(policyStatusAttribute())
+	if err != nil {
+		return nil, fmt.Errorf("status: %w", err)
+	}
+	return map[string]schema.Attribute{
+		"apiversion": schema.StringAttribute{
+			Computed: true,
+		},
+		"kind": schema.StringAttribute{
+			Computed: true,
+		},
+		"metadata": dataSourceMetadataAttribute(),
+		"spec": schema.SingleNestedAttribute{
+			Description: "The policy rule.",
+			Computed:    true,
+			Attributes:  rule,
+		},
+		"specs": schema.ListNestedAttribute{
+			Description: "The policy rules, for policies made of several rules.",
+			Computed:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: rule,
+			},
+		},
+		"spec_json": schema.StringAttribute{
+			Description: "The policy rule encoded as JSON, as found in the manifest of the policy.",
+			Computed:    true,
+		},
+		"specs_json": schema.StringAttribute{
+			Description: "The policy rules encoded as JSON, as found in the manifest of the policy.",
+			Computed:    true,
+		},
+		"status": status,
+	}, nil
+}
+
 // Read refreshes the Terraform state with the latest data.
 func (d *ciliumNetworkPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
-	cnpl, err := d.client.ListCiliumNetworkPolicies(ctx, "", metav1.ListOptions{})
-	assertNoError(err)
+	var state ciliumNetworkPoliciesDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	tflog.Debug(ctx, "DBG CNPL", map[string]interface{}{
-		"cn result": cnpl,
+	cnpl, err := d.client.ListCiliumNetworkPolicies(ctx, state.Namespace.ValueString(), metav1.ListOptions{
+		LabelSelector: state.LabelSelector.ValueString(),
+		FieldSelector: state.FieldSelector.ValueString(),
 	})
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to List CiliumNetworkPolicies", kubernetesErrorDetail(err))
+		return
+	}
+	tflog.Debug(ctx, "Listed CiliumNetworkPolicies", map[string]interface{}{"count": len(cnpl.Items)})
 
-	tflog.Debug(ctx, "DBG CNPL items", map[string]interface{}{
-		"cnp.Name: ": cnpl.Items,
-	})
+	state.CiliumNetworkPolicies = nil
+	listed := make([]listedObject, 0, len(cnpl.Items))
+	for i := range cnpl.Items {
+		cnp := &cnpl.Items[i]
+		policy, err := flattenCiliumNetworkPolicyModel(cnp)
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Encode CiliumNetworkPolicy", err.Error())
+			return
+		}
+		state.CiliumNetworkPolicies = append(state.CiliumNetworkPolicies, policy)
+		listed = append(listed, newListedObject(cnp.ObjectMeta, []interface{}{cnp.Spec, cnp.Specs}, cnp.Status))
+	}
+
+	id, contentHash, err := listIdentity(d.client.clusterIdentity(), listed)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Hash CiliumNetworkPolicies", err.Error())
+		return
+	}
+	state.ID = types.StringValue(id)
+	state.ContentHash = types.StringValue(contentHash)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
 
 // Configure enables provider-level data or clients to be set in the
@@ -73,16 +169,71 @@ func (d *ciliumNetworkPolicyDataSource) Configure(_ context.Context, req datasou
 	if req.ProviderData == nil {
 		return
 	}
-	d.client = req.ProviderData.(*CiliumClient)
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
 }
 
-// coffeesDataSourceModel maps the data source schema data.
+// ciliumNetworkPoliciesDataSourceModel maps the data source schema data.
 type ciliumNetworkPoliciesDataSourceModel struct {
-	ciliumNetworkPolicies []ciliumNetworkPolicyModel `tfsdk:"ciliumnetworkpolicies"`
+	ID                    types.String               `tfsdk:"id"`
+	ContentHash           types.String               `tfsdk:"content_hash"`
+	Namespace             types.String               `tfsdk:"namespace"`
+	LabelSelector         types.String               `tfsdk:"label_selector"`
+	FieldSelector         types.String               `tfsdk:"field_selector"`
+	CiliumNetworkPolicies []ciliumNetworkPolicyModel `tfsdk:"ciliumnetworkpolicies"`
 }
 
 // ciliumNetworkPolicyModel maps ciliumNetworkPolicys schema data.
 type ciliumNetworkPolicyModel struct {
-	ApiVersion types.String `tfsdk:"apiVersion"`
-	Kind       types.String `tfsdk:"kind"`
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Spec       *ruleModel                 `tfsdk:"spec"`
+	Specs      []ruleModel                `tfsdk:"specs"`
+	SpecJSON   types.String               `tfsdk:"spec_json"`
+	SpecsJSON  types.String               `tfsdk:"specs_json"`
+	Status     *policyStatusModel         `tfsdk:"status"`
+}
+
+// flattenCiliumNetworkPolicyModel converts a CiliumNetworkPolicy into the
+// model shared by the policy data sources.
+func flattenCiliumNetworkPolicyModel(cnp *ciliumv2.CiliumNetworkPolicy) (ciliumNetworkPolicyModel, error) {
+	m := ciliumNetworkPolicyModel{
+		ApiVersion: types.StringValue(ciliumv2.SchemeGroupVersion.String()),
+		Kind:       types.StringValue(ciliumv2.CNPKindDefinition),
+		Metadata:   flattenDataSourceObjectMeta(cnp.ObjectMeta),
+		Spec:       flattenRule(cnp.Spec),
+		Status:     flattenPolicyStatus(cnp.Status),
+	}
+	for _, rule := range cnp.Specs {
+		m.Specs = append(m.Specs, *flattenRule(rule))
+	}
+
+	var err error
+	if m.SpecJSON, err = jsonValueOrNull(cnp.Spec, cnp.Spec == nil); err != nil {
+		return m, err
+	}
+	if m.SpecsJSON, err = jsonValueOrNull(cnp.Specs, len(cnp.Specs) == 0); err != nil {
+		return m, err
+	}
+	return m, nil
+}
+
+// jsonValueOrNull encodes v as a JSON string, or returns null if isNull.
+func jsonValueOrNull(v interface{}, isNull bool) (types.String, error) {
+	if isNull {
+		return types.StringNull(), nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return types.StringNull(), err
+	}
+	return types.StringValue(string(data)), nil
 }
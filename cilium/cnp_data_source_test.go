@@ -0,0 +1,130 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+func TestCiliumNetworkPolicyDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+
+	data, err := os.ReadFile("../examples/resources/cnp_fqdn.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fqdn ciliumv2.CiliumNetworkPolicy
+	if err := yaml.Unmarshal(data, &fqdn); err != nil {
+		t.Fatal(err)
+	}
+	fqdn.Labels = map[string]string{"team": "java"}
+	fqdn.Status.Nodes = map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{
+		"node-1": {
+			OK:          true,
+			Enforcing:   true,
+			LastUpdated: slimv1.Date(2023, 4, 1, 12, 0, 0, 0, slimv1.Now().Location()),
+			Revision:    42,
+			Annotations: map[string]string{"team": "dns"},
+		},
+	}
+	other := &ciliumv2.CiliumNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "default"},
+		Spec:       fqdn.Spec.DeepCopy(),
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&fqdn, other)}
+
+	tests := []struct {
+		name   string
+		values map[string]tftypes.Value
+		want   []string
+	}{
+		{name: "all namespaces", want: []string{"other", "fqdn"}},
+		{name: "namespace", values: map[string]tftypes.Value{"namespace": tftypes.NewValue(tftypes.String, "netpols")}, want: []string{"fqdn"}},
+		{name: "label selector", values: map[string]tftypes.Value{"label_selector": tftypes.NewValue(tftypes.String, "team=java")}, want: []string{"fqdn"}},
+		{name: "no match", values: map[string]tftypes.Value{"label_selector": tftypes.NewValue(tftypes.String, "team=go")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, diags := testReadDataSource(t, NewCiliumNetworkPolicyDataSource(), client, tt.values)
+			if diags.HasError() {
+				t.Fatal(diags)
+			}
+			var model ciliumNetworkPoliciesDataSourceModel
+			if diags := state.Get(ctx, &model); diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			var names []string
+			for _, policy := range model.CiliumNetworkPolicies {
+				names = append(names, policy.Metadata.Name.ValueString())
+			}
+			if len(names) != len(tt.want) {
+				t.Fatalf("got policies %v, want %v", names, tt.want)
+			}
+			for i := range names {
+				if names[i] != tt.want[i] {
+					t.Errorf("got policies %v, want %v", names, tt.want)
+				}
+			}
+		})
+	}
+
+	state, _ := testReadDataSource(t, NewCiliumNetworkPolicyDataSource(), client, map[string]tftypes.Value{
+		"namespace": tftypes.NewValue(tftypes.String, "netpols"),
+	})
+	var model ciliumNetworkPoliciesDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	policy := model.CiliumNetworkPolicies[0]
+
+	if policy.Kind.ValueString() != "CiliumNetworkPolicy" || policy.ApiVersion.ValueString() != "cilium.io/v2" {
+		t.Errorf("unexpected type %s %s", policy.ApiVersion, policy.Kind)
+	}
+	if got := policy.Spec.EndpointSelector.MatchLabels["org"]; got != "java" {
+		t.Errorf("spec.endpoint_selector.match_labels.org = %q, want java", got)
+	}
+	if got := policy.Spec.Egress[0].ToFQDNs[0].MatchName.ValueString(); got != "api.twitter.com" {
+		t.Errorf("spec.egress.0.to_fqdns.0.match_name = %q", got)
+	}
+	if !policy.SpecsJSON.IsNull() {
+		t.Errorf("specs_json = %s, want null", policy.SpecsJSON)
+	}
+	want, _ := json.Marshal(fqdn.Spec)
+	if policy.SpecJSON.ValueString() != string(want) {
+		t.Errorf("spec_json = %s, want %s", policy.SpecJSON.ValueString(), want)
+	}
+	status := policy.Status.Nodes["node-1"]
+	if !status.OK.ValueBool() || !status.Enforcing.ValueBool() || status.LastUpdated.ValueString() != "2023-04-01T12:00:00Z" ||
+		status.Revision.ValueInt64() != 42 || status.Annotations["team"] != "dns" {
+		t.Errorf("unexpected node status %+v", status)
+	}
+}
+
+func TestAccCiliumNetworkPolicyDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `data "cilium_ciliumNetworkPolicies" "test" {
+  namespace = "kube-system"
+}`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.cilium_ciliumNetworkPolicies.test", "ciliumnetworkpolicies.#"),
+				),
+			},
+		},
+	})
+}
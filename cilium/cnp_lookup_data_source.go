@@ -0,0 +1,138 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &ciliumNetworkPolicyLookupDataSource{}
+	_ datasource.DataSourceWithConfigure = &ciliumNetworkPolicyLookupDataSource{}
+)
+
+// NewCiliumNetworkPolicyLookupDataSource is a helper function to simplify the provider implementation.
+func NewCiliumNetworkPolicyLookupDataSource() datasource.DataSource {
+	return &ciliumNetworkPolicyLookupDataSource{}
+}
+
+// ciliumNetworkPolicyLookupDataSource reads a single CiliumNetworkPolicy by
+// namespace and name.
+type ciliumNetworkPolicyLookupDataSource struct {
+	client *CiliumClient
+}
+
+// Metadata returns the data source type name.
+func (d *ciliumNetworkPolicyLookupDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_policy"
+}
+
+// Schema defines the schema for the data source.
+func (d *ciliumNetworkPolicyLookupDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	attributes, err := ciliumNetworkPolicyAttributes()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Data Source Schema", err.Error())
+		return
+	}
+	attributes["id"] = schema.StringAttribute{
+		Description: "Identifier of the policy, in the form `namespace/name`.",
+		Computed:    true,
+	}
+	attributes["name"] = schema.StringAttribute{
+		Description: "Name of the policy.",
+		Required:    true,
+	}
+	attributes["namespace"] = schema.StringAttribute{
+		Description: "Namespace of the policy. Defaults to `default`.",
+		Optional:    true,
+		Computed:    true,
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads a CiliumNetworkPolicy by namespace and name.",
+		Attributes:  attributes,
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *ciliumNetworkPolicyLookupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ciliumNetworkPolicyLookupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	namespace, name := state.Namespace.ValueString(), state.Name.ValueString()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	cnp, err := d.client.GetCiliumNetworkPolicy(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError(
+			"CiliumNetworkPolicy Not Found",
+			fmt.Sprintf("No CiliumNetworkPolicy named %q exists in namespace %q.", name, namespace),
+		)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read CiliumNetworkPolicy", kubernetesErrorDetail(err))
+		return
+	}
+
+	policy, err := flattenCiliumNetworkPolicyModel(cnp)
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Encode CiliumNetworkPolicy", err.Error())
+		return
+	}
+	state.ID = types.StringValue(namespacedID(cnp.Namespace, cnp.Name))
+	state.Namespace = types.StringValue(cnp.Namespace)
+	state.ApiVersion = policy.ApiVersion
+	state.Kind = policy.Kind
+	state.Metadata = policy.Metadata
+	state.Spec = policy.Spec
+	state.Specs = policy.Specs
+	state.SpecJSON = policy.SpecJSON
+	state.SpecsJSON = policy.SpecsJSON
+	state.Status = policy.Status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
+}
+
+// Configure enables provider-level data or clients to be set in the
+// provider-defined DataSource type. It is separately executed for each
+// ReadDataSource RPC.
+func (d *ciliumNetworkPolicyLookupDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.client = client
+}
+
+// ciliumNetworkPolicyLookupDataSourceModel maps the data source schema data,
+// the attributes of ciliumNetworkPolicyModel plus the lookup key.
+type ciliumNetworkPolicyLookupDataSourceModel struct {
+	ID         types.String               `tfsdk:"id"`
+	Name       types.String               `tfsdk:"name"`
+	Namespace  types.String               `tfsdk:"namespace"`
+	ApiVersion types.String               `tfsdk:"apiversion"`
+	Kind       types.String               `tfsdk:"kind"`
+	Metadata   *dataSourceObjectMetaModel `tfsdk:"metadata"`
+	Spec       *ruleModel                 `tfsdk:"spec"`
+	Specs      []ruleModel                `tfsdk:"specs"`
+	SpecJSON   types.String               `tfsdk:"spec_json"`
+	SpecsJSON  types.String               `tfsdk:"specs_json"`
+	Status     *policyStatusModel         `tfsdk:"status"`
+}
@@ -0,0 +1,84 @@
+package cilium
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+func TestCiliumNetworkPolicyLookupDataSourceRead(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&ciliumv2.CiliumNetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "allow-dns"},
+		Spec: &api.Rule{
+			EndpointSelector: api.NewESFromMatchRequirements(map[string]string{labels.GetExtendedKeyFrom("app"): "web"}, nil),
+		},
+	})}
+
+	state, diags := testReadDataSource(t, NewCiliumNetworkPolicyLookupDataSource(), client, map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "allow-dns"),
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	var model ciliumNetworkPolicyLookupDataSourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "default/allow-dns" || model.Namespace.ValueString() != "default" {
+		t.Errorf("unexpected id %s or namespace %s", model.ID, model.Namespace)
+	}
+	if model.Spec == nil || model.Spec.EndpointSelector.MatchLabels["app"] != "web" || model.SpecJSON.IsNull() {
+		t.Errorf("unexpected spec %+v", model.Spec)
+	}
+
+	_, diags = testReadDataSource(t, NewCiliumNetworkPolicyLookupDataSource(), client, map[string]tftypes.Value{
+		"name":      tftypes.NewValue(tftypes.String, "allow-dns"),
+		"namespace": tftypes.NewValue(tftypes.String, "kube-system"),
+	})
+	if !diags.HasError() || diags[0].Summary() != "CiliumNetworkPolicy Not Found" || !strings.Contains(diags[0].Detail(), `"kube-system"`) {
+		t.Errorf("unexpected diagnostics %v", diags)
+	}
+}
+
+func TestAccCiliumNetworkPolicyLookupDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_network_policy" "test" {
+  metadata = {
+    name = "tf-acc-lookup"
+  }
+  spec = {
+    endpoint_selector = {
+      match_labels = {
+        app = "web"
+      }
+    }
+  }
+}
+
+data "cilium_network_policy" "test" {
+  name = cilium_network_policy.test.metadata.name
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.cilium_network_policy.test", "id", "default/tf-acc-lookup"),
+					resource.TestCheckResourceAttr("data.cilium_network_policy.test", "spec.endpoint_selector.match_labels.app", "web"),
+				),
+			},
+		},
+	})
+}
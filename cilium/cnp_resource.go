@@ -0,0 +1,200 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumNetworkPolicyResource{}
+	_ resource.ResourceWithConfigure      = &ciliumNetworkPolicyResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumNetworkPolicyResource{}
+	_ resource.ResourceWithImportState    = &ciliumNetworkPolicyResource{}
+)
+
+// NewCiliumNetworkPolicyResource is a helper function to simplify the provider implementation.
+func NewCiliumNetworkPolicyResource() resource.Resource {
+	return &ciliumNetworkPolicyResource{}
+}
+
+// ciliumNetworkPolicyResourceModel maps the resource schema data.
+type ciliumNetworkPolicyResourceModel struct {
+	ID                 types.String             `tfsdk:"id"`
+	Metadata           *objectMetaModel         `tfsdk:"metadata"`
+	Spec               *ruleModel               `tfsdk:"spec"`
+	Specs              []ruleModel              `tfsdk:"specs"`
+	WaitForRealization *waitForRealizationModel `tfsdk:"wait_for_realization"`
+	Status             types.Object             `tfsdk:"status"`
+}
+
+// ciliumNetworkPolicyResource is the resource implementation.
+type ciliumNetworkPolicyResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumNetworkPolicyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumNetworkPolicyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_policy"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumNetworkPolicyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := policyAttributes("Identifier of the policy in namespace/name form.")
+	attributes["metadata"] = metadataAttribute(true)
+	resp.Schema = schema.Schema{
+		Description: "Manages a CiliumNetworkPolicy, a namespaced Cilium policy.",
+		Attributes:  attributes,
+	}
+}
+
+// ValidateConfig checks that the policy holds at least one rule and that
+// every rule selects the endpoints it applies to.
+func (r *ciliumNetworkPolicyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	resp.Diagnostics.Append(validatePolicyRules(ctx, req.Config, false)...)
+	resp.Diagnostics.Append(validateWaitForRealization(ctx, req.Config)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumNetworkPolicyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies := networkPolicyClient(r.client)
+	created := policies.createPolicy(ctx, plan.Metadata.objectMeta(), plan.Spec, plan.Specs, &resp.Diagnostics)
+	if created == nil {
+		return
+	}
+
+	plan.ID = types.StringValue(namespacedID(created.Namespace, created.Name))
+	plan.Metadata.Namespace = types.StringValue(created.Namespace)
+	plan.Metadata.UID = types.StringValue(string(created.UID))
+
+	status, diags := policyStatusObject(ctx, created.Status)
+	resp.Diagnostics.Append(diags...)
+	plan.Status = status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	policies.waitForRealization(ctx, plan.WaitForRealization, created, &resp.State, &resp.Diagnostics)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumNetworkPolicyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseNamespacedID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Network Policy ID", err.Error())
+		return
+	}
+
+	// A state without metadata comes from an import.
+	cnp := networkPolicyClient(r.client).readPolicy(ctx, namespace, name, state.Metadata == nil, &resp.Diagnostics)
+	if cnp == nil {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	refreshed, diags := flattenCiliumNetworkPolicy(ctx, cnp)
+	resp.Diagnostics.Append(diags...)
+	refreshed.WaitForRealization = state.WaitForRealization
+	resp.Diagnostics.Append(resp.State.Set(ctx, refreshed)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumNetworkPolicyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	policies := networkPolicyClient(r.client)
+	updated := policies.updatePolicy(ctx, plan.Metadata.objectMeta(), plan.Spec, plan.Specs, &resp.Diagnostics)
+	if updated == nil {
+		return
+	}
+
+	plan.ID = types.StringValue(namespacedID(updated.Namespace, updated.Name))
+	plan.Metadata.UID = types.StringValue(string(updated.UID))
+
+	status, diags := policyStatusObject(ctx, updated.Status)
+	resp.Diagnostics.Append(diags...)
+	plan.Status = status
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	policies.waitForRealization(ctx, plan.WaitForRealization, updated, &resp.State, &resp.Diagnostics)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumNetworkPolicyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumNetworkPolicyResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace, name, err := parseNamespacedID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Network Policy ID", err.Error())
+		return
+	}
+
+	networkPolicyClient(r.client).deletePolicy(ctx, namespace, name, &resp.Diagnostics)
+}
+
+// ImportState imports a policy by its namespace/name. A bare name imports
+// the policy from the default namespace.
+func (r *ciliumNetworkPolicyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	namespace, name, err := parseNamespacedID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), namespacedID(namespace, name))...)
+}
+
+// flattenCiliumNetworkPolicy converts a CiliumNetworkPolicy into the
+// resource model.
+func flattenCiliumNetworkPolicy(ctx context.Context, cnp *ciliumv2.CiliumNetworkPolicy) (ciliumNetworkPolicyResourceModel, diag.Diagnostics) {
+	m := ciliumNetworkPolicyResourceModel{
+		ID:       types.StringValue(namespacedID(cnp.Namespace, cnp.Name)),
+		Metadata: flattenObjectMeta(cnp.ObjectMeta),
+	}
+	var diags diag.Diagnostics
+	m.Spec, m.Specs, m.Status, diags = flattenPolicy(ctx, cnp)
+	return m, diags
+}
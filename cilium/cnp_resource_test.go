@@ -0,0 +1,158 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+func TestAccCiliumNetworkPolicyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_network_policy" "test" {
+  metadata = {
+    name = "tf-acc-allow-dns"
+  }
+  spec = {
+    endpoint_selector = {
+      match_labels = {
+        app = "test"
+      }
+    }
+    egress = [
+      {
+        to_endpoints = [
+          {
+            match_labels = {
+              "k8s:io.kubernetes.pod.namespace" = "kube-system"
+              "k8s:k8s-app"                     = "kube-dns"
+            }
+          },
+        ]
+        to_ports = [
+          {
+            ports = [
+              {
+                port     = "53"
+                protocol = "ANY"
+              },
+            ]
+          },
+        ]
+      },
+    ]
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_network_policy.test", "id", "default/tf-acc-allow-dns"),
+					resource.TestCheckResourceAttr("cilium_network_policy.test", "metadata.namespace", "default"),
+					resource.TestCheckResourceAttrSet("cilium_network_policy.test", "metadata.uid"),
+					resource.TestCheckResourceAttr("cilium_network_policy.test", "spec.endpoint_selector.match_labels.app", "test"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:      "cilium_network_policy.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestCiliumNetworkPolicyResourceImport(t *testing.T) {
+	ctx := context.Background()
+
+	data, err := os.ReadFile("../examples/resources/cnp_fqdn.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fqdn ciliumv2.CiliumNetworkPolicy
+	if err := yaml.Unmarshal(data, &fqdn); err != nil {
+		t.Fatal(err)
+	}
+	fqdn.Annotations = map[string]string{
+		lastAppliedConfigAnnotation: string(data),
+		"team":                      "payments",
+	}
+	fqdn.Status.Nodes = map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{
+		"node-1": {OK: true, Enforcing: true, Revision: 7},
+		"node-2": {Error: "unable to resolve FQDN selector"},
+	}
+
+	var groups ciliumv2.CiliumNetworkPolicy
+	if err := yaml.Unmarshal([]byte(`
+metadata:
+  name: groups
+  namespace: netpols
+spec:
+  endpointSelector:
+    matchLabels:
+      app: web
+  egress:
+  - toGroups:
+    - aws:
+        securityGroupsIds:
+        - sg-0123456789
+`), &groups); err != nil {
+		t.Fatal(err)
+	}
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset(&fqdn, &groups)}
+
+	state, diags := testImportResource(t, NewCiliumNetworkPolicyResource(), client, "netpols/fqdn")
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+	var model ciliumNetworkPolicyResourceModel
+	if diags := state.Get(ctx, &model); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if model.ID.ValueString() != "netpols/fqdn" || model.Metadata.Namespace.ValueString() != "netpols" {
+		t.Errorf("unexpected id %s or metadata %+v", model.ID, model.Metadata)
+	}
+	if len(model.Metadata.Annotations) != 1 || model.Metadata.Annotations["team"] != "payments" {
+		t.Errorf("annotations = %v, want only the team annotation", model.Metadata.Annotations)
+	}
+	var status policyStatusModel
+	if diags := model.Status.As(ctx, &status, basetypes.ObjectAsOptions{}); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if n1, n2 := status.Nodes["node-1"], status.Nodes["node-2"]; !n1.Enforcing.ValueBool() || n1.Revision.ValueInt64() != 7 ||
+		n2.OK.ValueBool() || n2.Error.ValueString() != "unable to resolve FQDN selector" {
+		t.Errorf("unexpected status %+v", status)
+	}
+	spec, _, err := expandPolicyRules(model.Spec, model.Specs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := json.Marshal(spec)
+	want, _ := json.Marshal(fqdn.Spec)
+	if string(got) != string(want) {
+		t.Errorf("imported spec\n%s\nwant\n%s", got, want)
+	}
+
+	_, diags = testImportResource(t, NewCiliumNetworkPolicyResource(), client, "netpols/groups")
+	if diags.HasError() || diags.WarningsCount() != 1 || diags[0].Summary() != "Cilium Network Policy Not Fully Imported" {
+		t.Errorf("expected a warning about unsupported fields, got %v", diags)
+	}
+
+	for _, id := range []string{"", "a/b/c"} {
+		_, diags = testImportResource(t, NewCiliumNetworkPolicyResource(), client, id)
+		if !diags.HasError() || diags[0].Summary() != "Invalid Import ID" {
+			t.Errorf("import of %q: expected an invalid ID error, got %v", id, diags)
+		}
+	}
+}
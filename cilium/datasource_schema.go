@@ -0,0 +1,88 @@
+package cilium
+
+import (
+	"fmt"
+
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+// computedAttributes converts resource schema attributes into computed data
+// source attributes, so that data sources expose objects with the same
+// nested attributes as the resources managing them.
+func computedAttributes(attributes map[string]schema.Attribute) (map[string]dsschema.Attribute, error) {
+	computed := make(map[string]dsschema.Attribute, len(attributes))
+	for name, attribute := range attributes {
+		var err error
+		if computed[name], err = computedAttribute(attribute); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return computed, nil
+}
+
+// computedAttribute converts a resource schema attribute into a computed
+// data source attribute. Every attribute type of the framework is supported;
+// an error is only returned for attribute types implemented outside of it.
+func computedAttribute(attribute schema.Attribute) (dsschema.Attribute, error) {
+	switch a := attribute.(type) {
+	case schema.StringAttribute:
+		return dsschema.StringAttribute{Description: a.Description, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.BoolAttribute:
+		return dsschema.BoolAttribute{Description: a.Description, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.Int64Attribute:
+		return dsschema.Int64Attribute{Description: a.Description, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.Float64Attribute:
+		return dsschema.Float64Attribute{Description: a.Description, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.NumberAttribute:
+		return dsschema.NumberAttribute{Description: a.Description, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.ListAttribute:
+		return dsschema.ListAttribute{Description: a.Description, ElementType: a.ElementType, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.SetAttribute:
+		return dsschema.SetAttribute{Description: a.Description, ElementType: a.ElementType, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.MapAttribute:
+		return dsschema.MapAttribute{Description: a.Description, ElementType: a.ElementType, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.ObjectAttribute:
+		return dsschema.ObjectAttribute{Description: a.Description, AttributeTypes: a.AttributeTypes, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.SingleNestedAttribute:
+		attributes, err := computedAttributes(a.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		return dsschema.SingleNestedAttribute{Description: a.Description, Attributes: attributes, Sensitive: a.Sensitive, Computed: true}, nil
+	case schema.ListNestedAttribute:
+		attributes, err := computedAttributes(a.NestedObject.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		return dsschema.ListNestedAttribute{
+			Description:  a.Description,
+			NestedObject: dsschema.NestedAttributeObject{Attributes: attributes},
+			Sensitive:    a.Sensitive,
+			Computed:     true,
+		}, nil
+	case schema.SetNestedAttribute:
+		attributes, err := computedAttributes(a.NestedObject.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		return dsschema.SetNestedAttribute{
+			Description:  a.Description,
+			NestedObject: dsschema.NestedAttributeObject{Attributes: attributes},
+			Sensitive:    a.Sensitive,
+			Computed:     true,
+		}, nil
+	case schema.MapNestedAttribute:
+		attributes, err := computedAttributes(a.NestedObject.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		return dsschema.MapNestedAttribute{
+			Description:  a.Description,
+			NestedObject: dsschema.NestedAttributeObject{Attributes: attributes},
+			Sensitive:    a.Sensitive,
+			Computed:     true,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported attribute type %T", attribute)
+}
@@ -0,0 +1,47 @@
+package cilium
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+)
+
+func TestComputedAttributesOfResourceSchemas(t *testing.T) {
+	ctx := context.Background()
+	resources := New().Resources(ctx)
+	if len(resources) == 0 {
+		t.Fatal("the provider has no resources")
+	}
+	for _, newResource := range resources {
+		r := newResource()
+		var metadataResp resource.MetadataResponse
+		r.Metadata(ctx, resource.MetadataRequest{ProviderTypeName: "cilium"}, &metadataResp)
+		var schemaResp resource.SchemaResponse
+		r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+
+		computed, err := computedAttributes(schemaResp.Schema.Attributes)
+		if err != nil {
+			t.Errorf("%s: %v", metadataResp.TypeName, err)
+			continue
+		}
+		for name, attribute := range computed {
+			if !attribute.IsComputed() || attribute.IsOptional() || attribute.IsRequired() {
+				t.Errorf("%s: attribute %s is not only computed", metadataResp.TypeName, name)
+			}
+		}
+	}
+}
+
+func TestComputedAttributesUnsupportedType(t *testing.T) {
+	type customAttribute struct{ schema.StringAttribute }
+
+	_, err := computedAttributes(map[string]schema.Attribute{
+		"spec": schema.SingleNestedAttribute{Attributes: map[string]schema.Attribute{"custom": customAttribute{}}},
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "spec: custom: unsupported attribute type") {
+		t.Errorf("got error %v, want an unsupported type error naming the attribute", err)
+	}
+}
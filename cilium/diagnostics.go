@@ -0,0 +1,78 @@
+package cilium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// clientConfigError reports that the Kubernetes client could not be created
+// from the provider configuration, as opposed to an error returned by the
+// Kubernetes API.
+type clientConfigError struct {
+	err error
+}
+
+func (e *clientConfigError) Error() string {
+	return "unable to create the Kubernetes client: " + e.err.Error()
+}
+
+func (e *clientConfigError) Unwrap() error {
+	return e.err
+}
+
+// kubernetesErrorReason classifies an error returned by the Kubernetes API
+// into one of the status reasons practitioners can act upon.
+func kubernetesErrorReason(err error) metav1.StatusReason {
+	switch {
+	case apierrors.IsNotFound(err):
+		return metav1.StatusReasonNotFound
+	case apierrors.IsForbidden(err):
+		return metav1.StatusReasonForbidden
+	case apierrors.IsUnauthorized(err):
+		return metav1.StatusReasonUnauthorized
+	case apierrors.IsTimeout(err), apierrors.IsServerTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		return metav1.StatusReasonTimeout
+	}
+	return apierrors.ReasonForError(err)
+}
+
+// kubernetesErrorHint returns a remediation hint for the given status reason.
+func kubernetesErrorHint(reason metav1.StatusReason) string {
+	switch reason {
+	case metav1.StatusReasonNotFound:
+		return "Ensure the object exists and that the Cilium CRDs are installed in the cluster, e.g. with `kubectl get crd | grep cilium.io`."
+	case metav1.StatusReasonForbidden:
+		return "The credentials used by the provider are not allowed to perform this operation. Grant the user or service account RBAC permissions on the cilium.io resources."
+	case metav1.StatusReasonUnauthorized:
+		return "The Kubernetes API rejected the provider credentials. Check that the token, client certificate or exec plugin configured for the provider is valid and not expired."
+	case metav1.StatusReasonTimeout:
+		return "The Kubernetes API did not answer in time. Check that the API server is reachable from where Terraform runs and retry."
+	case metav1.StatusReasonAlreadyExists:
+		return "An object with the same name already exists. Import it with `terraform import` or choose a different name."
+	case metav1.StatusReasonConflict:
+		return "The object was modified concurrently. Run `terraform refresh` and apply again."
+	case metav1.StatusReasonInvalid:
+		return "The Kubernetes API rejected the object as invalid. Fix the configuration according to the error above."
+	}
+	return "Check that the Kubernetes API server is reachable with the configured credentials. " +
+		"If the error is not clear, please contact the provider developers."
+}
+
+// kubernetesErrorDetail renders an error returned by the Kubernetes API as a
+// diagnostic detail carrying the status reason and a remediation hint.
+func kubernetesErrorDetail(err error) string {
+	var configErr *clientConfigError
+	if errors.As(err, &configErr) {
+		return fmt.Sprintf("%s\n\n%s", err, "Check the kube_config, config_context, host, credential, exec and in_cluster settings of the provider block.")
+	}
+
+	reason := kubernetesErrorReason(err)
+	if reason == metav1.StatusReasonUnknown {
+		return fmt.Sprintf("%s\n\n%s", err, kubernetesErrorHint(reason))
+	}
+	return fmt.Sprintf("%s (reason: %s)\n\n%s", err, reason, kubernetesErrorHint(reason))
+}
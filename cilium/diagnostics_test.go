@@ -0,0 +1,50 @@
+package cilium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestKubernetesErrorReason(t *testing.T) {
+	resource := schema.GroupResource{Group: "cilium.io", Resource: "ciliumnetworkpolicies"}
+
+	tests := []struct {
+		err  error
+		want metav1.StatusReason
+	}{
+		{apierrors.NewNotFound(resource, "allow-dns"), metav1.StatusReasonNotFound},
+		{apierrors.NewForbidden(resource, "allow-dns", errors.New("rbac")), metav1.StatusReasonForbidden},
+		{apierrors.NewUnauthorized("expired token"), metav1.StatusReasonUnauthorized},
+		{apierrors.NewTimeoutError("slow", 1), metav1.StatusReasonTimeout},
+		{fmt.Errorf("list: %w", context.DeadlineExceeded), metav1.StatusReasonTimeout},
+		{apierrors.NewAlreadyExists(resource, "allow-dns"), metav1.StatusReasonAlreadyExists},
+		{errors.New("connection refused"), metav1.StatusReasonUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := kubernetesErrorReason(tt.err); got != tt.want {
+			t.Errorf("kubernetesErrorReason(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestKubernetesErrorDetail(t *testing.T) {
+	resource := schema.GroupResource{Group: "cilium.io", Resource: "ciliumnodes"}
+
+	detail := kubernetesErrorDetail(apierrors.NewForbidden(resource, "", errors.New("rbac")))
+	if !strings.Contains(detail, "reason: Forbidden") || !strings.Contains(detail, "RBAC") {
+		t.Errorf("detail does not name the reason and hint: %s", detail)
+	}
+
+	detail = kubernetesErrorDetail(errors.New("dial tcp: connection refused"))
+	if strings.Contains(detail, "reason:") || !strings.Contains(detail, "connection refused") {
+		t.Errorf("unexpected detail for a non-API error: %s", detail)
+	}
+}
@@ -0,0 +1,234 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+// ciliumEnvoyConfig is a CiliumEnvoyConfig or CiliumClusterwideEnvoyConfig.
+// The Cilium API types this provider is built with predate the node
+// selector, and decode the Envoy resources only when their types are
+// registered, so the objects are read and written with these types instead.
+type ciliumEnvoyConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec envoyConfigSpec `json:"spec,omitempty"`
+}
+
+type envoyConfigSpec struct {
+	Services        []*ciliumv2.ServiceListener `json:"services,omitempty"`
+	BackendServices []*ciliumv2.Service         `json:"backendServices,omitempty"`
+	// NodeSelector restricts the nodes the configuration applies to.
+	NodeSelector *slimv1.LabelSelector    `json:"nodeSelector,omitempty"`
+	Resources    []map[string]interface{} `json:"resources,omitempty"`
+}
+
+func (c *ciliumEnvoyConfig) toUnstructured(kind string) (*unstructured.Unstructured, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(c)
+	if err != nil {
+		return nil, err
+	}
+	u := &unstructured.Unstructured{Object: obj}
+	u.SetGroupVersionKind(ciliumv2.SchemeGroupVersion.WithKind(kind))
+	return u, nil
+}
+
+func envoyConfigFromUnstructured(obj *unstructured.Unstructured) (*ciliumEnvoyConfig, error) {
+	c := &ciliumEnvoyConfig{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type envoyConfigSpecModel struct {
+	Services        []envoyServiceListenerModel `tfsdk:"services"`
+	BackendServices []envoyBackendServiceModel  `tfsdk:"backend_services"`
+	NodeSelector    *selectorModel              `tfsdk:"node_selector"`
+	Resources       []types.String              `tfsdk:"resources"`
+}
+
+type envoyServiceListenerModel struct {
+	Name      types.String `tfsdk:"name"`
+	Namespace types.String `tfsdk:"namespace"`
+	Listener  types.String `tfsdk:"listener"`
+}
+
+type envoyBackendServiceModel struct {
+	Name      types.String `tfsdk:"name"`
+	Namespace types.String `tfsdk:"namespace"`
+	Number    []string     `tfsdk:"number"`
+}
+
+// envoyConfigSpecAttribute returns the schema of the spec of Envoy
+// configurations. defaultNamespace describes the namespace services default
+// to.
+func envoyConfigSpecAttribute(defaultNamespace string) schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "The Envoy configuration.",
+		Required:    true,
+		Attributes: map[string]schema.Attribute{
+			"services": schema.ListNestedAttribute{
+				Description: "Kubernetes services whose traffic is redirected to an Envoy listener.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the service.",
+							Required:    true,
+						},
+						"namespace": schema.StringAttribute{
+							Description: "Namespace of the service, " + defaultNamespace + ".",
+							Optional:    true,
+						},
+						"listener": schema.StringAttribute{
+							Description: "Name of the listener of `resources` the traffic is redirected to. Defaults to the first listener.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"backend_services": schema.ListNestedAttribute{
+				Description: "Kubernetes services whose backends are synced to Envoy, without redirecting their traffic.",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Description: "Name of the service.",
+							Required:    true,
+						},
+						"namespace": schema.StringAttribute{
+							Description: "Namespace of the service, " + defaultNamespace + ".",
+							Optional:    true,
+						},
+						"number": schema.ListAttribute{
+							Description: "Ports of the service to sync. Defaults to all of them.",
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+			"node_selector": schema.SingleNestedAttribute{
+				Description: "Selects the nodes the configuration applies to by their labels. Defaults to all nodes. " +
+					"Requires Cilium 1.15 or newer.",
+				Optional:   true,
+				Attributes: selectorAttributes(),
+			},
+			"resources": schema.ListAttribute{
+				Description: "Envoy listeners, route configurations, clusters, cluster load assignments and secrets, " +
+					"each a JSON or YAML document with its `@type`, e.g. `type.googleapis.com/envoy.config.listener.v3.Listener`.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// validateEnvoyResources checks every known Envoy resource of the
+// configuration against the message of its @type.
+func validateEnvoyResources(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	p := path.Root("spec").AtName("resources")
+	var resources types.List
+	diags := config.GetAttribute(ctx, p, &resources)
+	if diags.HasError() || resources.IsNull() || resources.IsUnknown() {
+		return diags
+	}
+	for i, element := range resources.Elements() {
+		resource, ok := element.(types.String)
+		if !ok || resource.IsNull() || resource.IsUnknown() {
+			continue
+		}
+		if _, err := decodeEnvoyResource(resource.ValueString()); err != nil {
+			diags.AddAttributeError(p.AtListIndex(i), "Invalid Envoy Resource", err.Error())
+		}
+	}
+	return diags
+}
+
+// expand converts the spec model into the spec of an Envoy configuration.
+func (m *envoyConfigSpecModel) expand() (envoyConfigSpec, error) {
+	spec := envoyConfigSpec{
+		NodeSelector: optionalLabelSelector(m.NodeSelector),
+	}
+	for _, service := range m.Services {
+		spec.Services = append(spec.Services, &ciliumv2.ServiceListener{
+			Name:      service.Name.ValueString(),
+			Namespace: service.Namespace.ValueString(),
+			Listener:  service.Listener.ValueString(),
+		})
+	}
+	for _, service := range m.BackendServices {
+		spec.BackendServices = append(spec.BackendServices, &ciliumv2.Service{
+			Name:      service.Name.ValueString(),
+			Namespace: service.Namespace.ValueString(),
+			Ports:     service.Number,
+		})
+	}
+	for i, resource := range m.Resources {
+		if _, err := decodeEnvoyResource(resource.ValueString()); err != nil {
+			return spec, fmt.Errorf("resources[%d]: %w", i, err)
+		}
+		var decoded map[string]interface{}
+		if err := yaml.Unmarshal([]byte(resource.ValueString()), &decoded); err != nil {
+			return spec, fmt.Errorf("resources[%d]: %w", i, err)
+		}
+		spec.Resources = append(spec.Resources, decoded)
+	}
+	return spec, nil
+}
+
+// flattenEnvoyConfigSpec converts the spec of an Envoy configuration into
+// the spec model. Resources semantically equal to the one at the same index
+// of prior, the spec in state, keep their formatting; the others are
+// written as canonical JSON.
+func flattenEnvoyConfigSpec(spec envoyConfigSpec, prior *envoyConfigSpecModel) (*envoyConfigSpecModel, error) {
+	m := &envoyConfigSpecModel{
+		NodeSelector: flattenOptionalLabelSelector(spec.NodeSelector),
+	}
+	for _, service := range spec.Services {
+		m.Services = append(m.Services, envoyServiceListenerModel{
+			Name:      types.StringValue(service.Name),
+			Namespace: stringValueOrNull(service.Namespace),
+			Listener:  stringValueOrNull(service.Listener),
+		})
+	}
+	for _, service := range spec.BackendServices {
+		m.BackendServices = append(m.BackendServices, envoyBackendServiceModel{
+			Name:      types.StringValue(service.Name),
+			Namespace: stringValueOrNull(service.Namespace),
+			Number:    nilIfEmpty(service.Ports),
+		})
+	}
+	for i, resource := range spec.Resources {
+		data, err := json.Marshal(resource)
+		if err != nil {
+			return nil, err
+		}
+		live := string(data)
+		if prior != nil && i < len(prior.Resources) && envoyResourceEqual(prior.Resources[i].ValueString(), live) {
+			m.Resources = append(m.Resources, prior.Resources[i])
+			continue
+		}
+		if normalized, err := normalizeEnvoyResource(live); err == nil {
+			live = normalized
+		}
+		m.Resources = append(m.Resources, types.StringValue(live))
+	}
+	return m, nil
+}
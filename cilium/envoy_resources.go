@@ -0,0 +1,142 @@
+package cilium
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"sigs.k8s.io/yaml"
+
+	// Register the Envoy resources and extensions Cilium accepts in
+	// CiliumEnvoyConfigs, so resources can be decoded and validated like the
+	// agent does.
+	_ "github.com/cilium/proxy/go/envoy/config/cluster/v3"
+	_ "github.com/cilium/proxy/go/envoy/config/endpoint/v3"
+	_ "github.com/cilium/proxy/go/envoy/config/listener/v3"
+	_ "github.com/cilium/proxy/go/envoy/config/route/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/clusters/dynamic_forward_proxy/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/http/dynamic_forward_proxy/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/http/ext_authz/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/http/local_ratelimit/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/http/ratelimit/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/http/router/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/http/set_metadata/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/listener/tls_inspector/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/connection_limit/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/ext_authz/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/http_connection_manager/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/local_ratelimit/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/ratelimit/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/sni_cluster/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/sni_dynamic_forward_proxy/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/filters/network/tcp_proxy/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/transport_sockets/tls/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/upstreams/http/http/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/upstreams/http/tcp/v3"
+	_ "github.com/cilium/proxy/go/envoy/extensions/upstreams/http/v3"
+)
+
+// envoyResourceTypes lists the types of the Envoy resources a
+// CiliumEnvoyConfig may hold.
+var envoyResourceTypes = map[string]bool{
+	"type.googleapis.com/envoy.config.listener.v3.Listener":                true,
+	"type.googleapis.com/envoy.config.route.v3.RouteConfiguration":         true,
+	"type.googleapis.com/envoy.config.cluster.v3.Cluster":                  true,
+	"type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment":   true,
+	"type.googleapis.com/envoy.extensions.transport_sockets.tls.v3.Secret": true,
+}
+
+// decodeEnvoyResource decodes an Envoy resource written in JSON or YAML
+// and validates it against the message of its @type, including the
+// constraints Envoy declares on the fields.
+func decodeEnvoyResource(data string) (*anypb.Any, error) {
+	raw, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return nil, err
+	}
+	var header struct {
+		Type string `json:"@type"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return nil, fmt.Errorf("an Envoy resource must be an object: %w", err)
+	}
+	if header.Type == "" {
+		return nil, fmt.Errorf("missing @type")
+	}
+	if !envoyResourceTypes[header.Type] {
+		return nil, fmt.Errorf("unsupported @type %q, expected one of %s", header.Type, strings.Join(sortedEnvoyResourceTypes(), ", "))
+	}
+
+	resource := &anypb.Any{}
+	if err := protojson.Unmarshal(raw, resource); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", header.Type, err)
+	}
+	message, err := resource.UnmarshalNew()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", header.Type, err)
+	}
+	if validator, ok := message.(interface{ ValidateAll() error }); ok {
+		if err := validator.ValidateAll(); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", header.Type, err)
+		}
+	}
+	return resource, nil
+}
+
+func sortedEnvoyResourceTypes() []string {
+	var types []string
+	for t := range envoyResourceTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// normalizeEnvoyResource returns the canonical JSON encoding of an Envoy
+// resource: keys are sorted and values are written the way protojson
+// writes them, so that resources differing only in formatting, key order or
+// the spelling of values, e.g. `1s` and `1.000s`, are equal.
+func normalizeEnvoyResource(data string) (string, error) {
+	resource, err := decodeEnvoyResource(data)
+	if err != nil {
+		return "", err
+	}
+	return canonicalEnvoyJSON(resource)
+}
+
+func canonicalEnvoyJSON(resource proto.Message) (string, error) {
+	// protojson output is deliberately unstable, decode it again and let
+	// encoding/json sort the keys.
+	encoded, err := protojson.Marshal(resource)
+	if err != nil {
+		return "", err
+	}
+	var v interface{}
+	if err := json.Unmarshal(encoded, &v); err != nil {
+		return "", err
+	}
+	canonical, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(canonical), nil
+}
+
+// envoyResourceEqual reports whether two Envoy resources are semantically
+// equal. Resources which cannot be decoded are compared as JSON documents.
+func envoyResourceEqual(a, b string) bool {
+	na, errA := normalizeEnvoyResource(a)
+	nb, errB := normalizeEnvoyResource(b)
+	if errA == nil && errB == nil {
+		return na == nb
+	}
+	var va, vb interface{}
+	if yaml.Unmarshal([]byte(a), &va) != nil || yaml.Unmarshal([]byte(b), &vb) != nil {
+		return a == b
+	}
+	return manifestEqual(va, vb)
+}
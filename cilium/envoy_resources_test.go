@@ -0,0 +1,134 @@
+package cilium
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const testEnvoyCluster = `{
+  "@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster",
+  "name": "default/echo",
+  "connect_timeout": "5s",
+  "lb_policy": "ROUND_ROBIN",
+  "type": "EDS",
+  "outlier_detection": {"split_external_local_origin_errors": true, "consecutive_local_origin_failure": 2}
+}`
+
+func TestDecodeEnvoyResource(t *testing.T) {
+	for name, tc := range map[string]struct {
+		data      string
+		wantError string
+	}{
+		"cluster": {data: testEnvoyCluster},
+		"yaml listener": {data: `
+"@type": type.googleapis.com/envoy.config.listener.v3.Listener
+name: envoy-lb-listener
+filter_chains:
+- filters:
+  - name: envoy.filters.network.http_connection_manager
+    typed_config:
+      "@type": type.googleapis.com/envoy.extensions.filters.network.http_connection_manager.v3.HttpConnectionManager
+      stat_prefix: envoy-lb-listener
+      rds:
+        route_config_name: lb_route
+      http_filters:
+      - name: envoy.filters.http.router
+        typed_config:
+          "@type": type.googleapis.com/envoy.extensions.filters.http.router.v3.Router
+`},
+		"not an object":    {data: `- a`, wantError: "must be an object"},
+		"missing type":     {data: `{"name": "default/echo"}`, wantError: "missing @type"},
+		"unsupported type": {data: `{"@type": "type.googleapis.com/envoy.config.core.v3.Address"}`, wantError: "unsupported @type"},
+		"unknown field": {
+			data:      `{"@type": "type.googleapis.com/envoy.config.cluster.v3.Cluster", "name": "echo", "lb": "ROUND_ROBIN"}`,
+			wantError: "invalid type.googleapis.com/envoy.config.cluster.v3.Cluster",
+		},
+		"unknown nested type": {
+			data:      `{"@type": "type.googleapis.com/envoy.config.listener.v3.Listener", "name": "l", "filter_chains": [{"filters": [{"name": "f", "typed_config": {"@type": "type.googleapis.com/envoy.Unknown"}}]}]}`,
+			wantError: "invalid type.googleapis.com/envoy.config.listener.v3.Listener",
+		},
+		"constraint": {
+			data:      `{"@type": "type.googleapis.com/envoy.config.endpoint.v3.ClusterLoadAssignment"}`,
+			wantError: "ClusterName",
+		},
+	} {
+		_, err := decodeEnvoyResource(tc.data)
+		if tc.wantError == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tc.wantError) {
+			t.Errorf("%s: got error %v, want one containing %q", name, err, tc.wantError)
+		}
+	}
+}
+
+func TestEnvoyResourceEqual(t *testing.T) {
+	reordered := `
+"@type": type.googleapis.com/envoy.config.cluster.v3.Cluster
+type: EDS
+outlier_detection:
+  consecutive_local_origin_failure: 2
+  split_external_local_origin_errors: true
+lb_policy: ROUND_ROBIN
+connect_timeout: 5.000s
+name: default/echo
+`
+	if !envoyResourceEqual(testEnvoyCluster, reordered) {
+		t.Error("resources differing in key order, format and duration spelling should be equal")
+	}
+	// protojson accepts camelCase field names too.
+	camel := strings.Replace(testEnvoyCluster, "connect_timeout", "connectTimeout", 1)
+	if !envoyResourceEqual(testEnvoyCluster, camel) {
+		t.Error("resources differing in field name spelling should be equal")
+	}
+	changed := strings.Replace(testEnvoyCluster, `"5s"`, `"6s"`, 1)
+	if envoyResourceEqual(testEnvoyCluster, changed) {
+		t.Error("resources with different timeouts should differ")
+	}
+
+	normalized, err := normalizeEnvoyResource(reordered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := normalizeEnvoyResource(testEnvoyCluster)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalized != other || !strings.HasPrefix(normalized, `{"@type":"type.googleapis.com/envoy.config.cluster.v3.Cluster"`) {
+		t.Errorf("unexpected normalized resources\n%s\n%s", normalized, other)
+	}
+}
+
+func TestFlattenEnvoyConfigSpec(t *testing.T) {
+	m := &envoyConfigSpecModel{Resources: []types.String{types.StringValue(testEnvoyCluster)}}
+	spec, err := m.expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The resource as written in the configuration is kept while the live
+	// object is semantically equal to it.
+	flattened, err := flattenEnvoyConfigSpec(spec, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := flattened.Resources[0].ValueString(); got != testEnvoyCluster {
+		t.Errorf("resource was rewritten to %s", got)
+	}
+
+	// Changes made outside of Terraform are shown as canonical JSON.
+	spec.Resources[0]["connect_timeout"] = "6s"
+	flattened, err = flattenEnvoyConfigSpec(spec, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := normalizeEnvoyResource(strings.Replace(testEnvoyCluster, `"5s"`, `"6s"`, 1))
+	if got := flattened.Resources[0].ValueString(); got != want {
+		t.Errorf("drifted resource is %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,203 @@
+package cilium
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/go-homedir"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// inlineContextName names the cluster, user and context of the
+	// in-memory kubeconfig built from inline provider credentials.
+	inlineContextName = "terraform-provider-cilium"
+
+	// inClusterContextName names the cluster, user and context of the
+	// in-memory kubeconfig built from the pod service account.
+	inClusterContextName = "in-cluster"
+)
+
+const (
+	kubeConfigOriginAttribute   = "kube_config attribute"
+	kubeConfigOriginEnvironment = "KUBECONFIG environment variable"
+	kubeConfigOriginDefault     = "default path"
+)
+
+// kubeConfigSource describes which kubeconfig files the provider loads and
+// where that choice came from, so diagnostics can point at the right knob.
+type kubeConfigSource struct {
+	// Origin is a human readable description of where Paths came from.
+	Origin string
+
+	// Paths lists the kubeconfig files to load. When more than one path is
+	// present the files are merged with the usual client-go precedence
+	// rules, first file wins.
+	Paths []string
+
+	// Missing lists the entries of a KUBECONFIG list which do not exist.
+	Missing []string
+}
+
+// ExplicitPath returns the path to hand to genericclioptions.ConfigFlags.
+// An empty string lets client-go merge the KUBECONFIG list itself.
+func (s kubeConfigSource) ExplicitPath() string {
+	if len(s.Paths) == 1 {
+		return s.Paths[0]
+	}
+	return ""
+}
+
+func (s kubeConfigSource) String() string {
+	return fmt.Sprintf("%s (%s)", s.Origin, strings.Join(s.Paths, string(filepath.ListSeparator)))
+}
+
+// resolveKubeConfig determines which kubeconfig files to load. The
+// kube_config attribute takes precedence over the KUBECONFIG environment
+// variable, which in turn takes precedence over ~/.kube/config.
+func resolveKubeConfig(configured, env string) (kubeConfigSource, error) {
+	if configured != "" {
+		path, err := homedir.Expand(configured)
+		if err != nil {
+			return kubeConfigSource{}, fmt.Errorf("unable to expand kube_config path %q: %w", configured, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return kubeConfigSource{}, fmt.Errorf("unable to read kube_config path %q: %w", path, err)
+		}
+		return kubeConfigSource{Origin: kubeConfigOriginAttribute, Paths: []string{path}}, nil
+	}
+
+	if env != "" {
+		source := kubeConfigSource{Origin: kubeConfigOriginEnvironment}
+		for _, entry := range filepath.SplitList(env) {
+			if entry == "" {
+				continue
+			}
+			path, err := homedir.Expand(entry)
+			if err != nil {
+				return kubeConfigSource{}, fmt.Errorf("unable to expand KUBECONFIG entry %q: %w", entry, err)
+			}
+			if _, err := os.Stat(path); err != nil {
+				source.Missing = append(source.Missing, path)
+				continue
+			}
+			source.Paths = append(source.Paths, path)
+		}
+		if len(source.Paths) == 0 {
+			return kubeConfigSource{}, fmt.Errorf("none of the files listed in KUBECONFIG exist: %s", env)
+		}
+		return source, nil
+	}
+
+	home, err := homedir.Dir()
+	if err != nil {
+		return kubeConfigSource{}, fmt.Errorf("unable to determine home directory: %w", err)
+	}
+	path := filepath.Join(home, ".kube", "config")
+	if _, err := os.Stat(path); err != nil {
+		return kubeConfigSource{}, fmt.Errorf("no kube_config attribute or KUBECONFIG environment variable set and the default path %q is not readable: %w", path, err)
+	}
+	return kubeConfigSource{Origin: kubeConfigOriginDefault, Paths: []string{path}}, nil
+}
+
+// inlineRawConfig builds an in-memory kubeconfig from inline credentials so
+// that clients created without a kubeconfig file still expose a RawConfig.
+func inlineRawConfig(opts ClientOptions) clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = opts.Host
+	cluster.CertificateAuthorityData = []byte(opts.ClusterCACertificate)
+	cluster.InsecureSkipTLSVerify = opts.Insecure
+	config.Clusters[inlineContextName] = cluster
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.Token = opts.Token
+	authInfo.ClientCertificateData = []byte(opts.ClientCertificate)
+	authInfo.ClientKeyData = []byte(opts.ClientKey)
+	authInfo.Exec = opts.Exec
+	config.AuthInfos[inlineContextName] = authInfo
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = inlineContextName
+	context.AuthInfo = inlineContextName
+	config.Contexts[inlineContextName] = context
+	config.CurrentContext = inlineContextName
+
+	return *config
+}
+
+// inClusterRawConfig builds an in-memory kubeconfig equivalent to the given
+// in-cluster rest.Config. The token is referenced by file so that rotated
+// service account tokens are picked up.
+func inClusterRawConfig(restConfig *rest.Config) clientcmdapi.Config {
+	config := clientcmdapi.NewConfig()
+
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = restConfig.Host
+	cluster.CertificateAuthority = restConfig.TLSClientConfig.CAFile
+	config.Clusters[inClusterContextName] = cluster
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.TokenFile = restConfig.BearerTokenFile
+	if authInfo.TokenFile == "" {
+		authInfo.Token = restConfig.BearerToken
+	}
+	config.AuthInfos[inClusterContextName] = authInfo
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = inClusterContextName
+	context.AuthInfo = inClusterContextName
+	config.Contexts[inClusterContextName] = context
+	config.CurrentContext = inClusterContextName
+
+	return *config
+}
+
+// detectInCluster reports whether the provider should fall back to the pod
+// service account: it runs inside a pod and no other source was configured.
+func detectInCluster(kubeConfig, env string) bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != "" && kubeConfig == "" && env == ""
+}
+
+// clientConfigGetter adapts a clientcmd.ClientConfig which is not backed by
+// kubeconfig files to the genericclioptions.RESTClientGetter interface.
+type clientConfigGetter struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+func (g *clientConfigGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.clientConfig.ClientConfig()
+}
+
+func (g *clientConfigGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	config, err := g.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(discoveryClient), nil
+}
+
+func (g *clientConfigGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	discoveryClient, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient), nil
+}
+
+func (g *clientConfigGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return g.clientConfig
+}
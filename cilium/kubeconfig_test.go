@@ -0,0 +1,162 @@
+package cilium
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/go-homedir"
+	"k8s.io/client-go/rest"
+)
+
+func init() {
+	// HOME is swapped per test, so the cached value must not be reused.
+	homedir.DisableCache = true
+}
+
+func writeKubeConfig(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("apiVersion: v1\nkind: Config\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveKubeConfig(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	if err := os.MkdirAll(filepath.Join(home, ".kube"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	defaultPath := writeKubeConfig(t, filepath.Join(home, ".kube"), "config")
+
+	dir := t.TempDir()
+	first := writeKubeConfig(t, dir, "first")
+	second := writeKubeConfig(t, dir, "second")
+	missing := filepath.Join(dir, "missing")
+	list := strings.Join([]string{first, missing, second}, string(filepath.ListSeparator))
+
+	tests := []struct {
+		name       string
+		configured string
+		env        string
+		origin     string
+		paths      []string
+		missing    []string
+		explicit   string
+	}{
+		{
+			name:       "attribute wins over environment",
+			configured: first,
+			env:        second,
+			origin:     kubeConfigOriginAttribute,
+			paths:      []string{first},
+			explicit:   first,
+		},
+		{
+			name:       "attribute expands home directory",
+			configured: "~/.kube/config",
+			origin:     kubeConfigOriginAttribute,
+			paths:      []string{defaultPath},
+			explicit:   defaultPath,
+		},
+		{
+			name:     "environment list is merged",
+			env:      list,
+			origin:   kubeConfigOriginEnvironment,
+			paths:    []string{first, second},
+			missing:  []string{missing},
+			explicit: "",
+		},
+		{
+			name:     "single environment entry is explicit",
+			env:      second,
+			origin:   kubeConfigOriginEnvironment,
+			paths:    []string{second},
+			explicit: second,
+		},
+		{
+			name:     "default path is the last resort",
+			origin:   kubeConfigOriginDefault,
+			paths:    []string{defaultPath},
+			explicit: defaultPath,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, err := resolveKubeConfig(tt.configured, tt.env)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if source.Origin != tt.origin {
+				t.Errorf("origin = %q, want %q", source.Origin, tt.origin)
+			}
+			if !reflect.DeepEqual(source.Paths, tt.paths) {
+				t.Errorf("paths = %v, want %v", source.Paths, tt.paths)
+			}
+			if !reflect.DeepEqual(source.Missing, tt.missing) {
+				t.Errorf("missing = %v, want %v", source.Missing, tt.missing)
+			}
+			if got := source.ExplicitPath(); got != tt.explicit {
+				t.Errorf("explicit path = %q, want %q", got, tt.explicit)
+			}
+		})
+	}
+}
+
+func TestResolveKubeConfigErrors(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	dir := t.TempDir()
+
+	if _, err := resolveKubeConfig(filepath.Join(dir, "nope"), ""); err == nil {
+		t.Error("expected an error for a missing kube_config path")
+	}
+	if _, err := resolveKubeConfig("", filepath.Join(dir, "nope")); err == nil {
+		t.Error("expected an error when no KUBECONFIG entry exists")
+	}
+	if _, err := resolveKubeConfig("", ""); err == nil {
+		t.Error("expected an error when the default path does not exist")
+	}
+}
+
+func TestInClusterRawConfig(t *testing.T) {
+	raw := inClusterRawConfig(&rest.Config{
+		Host:            "https://10.96.0.1:443",
+		BearerToken:     "initial-token",
+		BearerTokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+		TLSClientConfig: rest.TLSClientConfig{CAFile: "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"},
+	})
+
+	if raw.CurrentContext != inClusterContextName {
+		t.Errorf("current context = %q", raw.CurrentContext)
+	}
+	if got := raw.Clusters[inClusterContextName].Server; got != "https://10.96.0.1:443" {
+		t.Errorf("server = %q", got)
+	}
+	authInfo := raw.AuthInfos[inClusterContextName]
+	if authInfo.TokenFile != "/var/run/secrets/kubernetes.io/serviceaccount/token" || authInfo.Token != "" {
+		t.Errorf("auth info should reference the token file, got %+v", authInfo)
+	}
+}
+
+func TestDetectInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	if detectInCluster("", "") {
+		t.Error("detected in-cluster outside of a pod")
+	}
+
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+	if !detectInCluster("", "") {
+		t.Error("expected in-cluster detection inside a pod")
+	}
+	if detectInCluster("~/.kube/config", "") {
+		t.Error("kube_config must disable in-cluster detection")
+	}
+	if detectInCluster("", "/tmp/kubeconfig") {
+		t.Error("KUBECONFIG must disable in-cluster detection")
+	}
+}
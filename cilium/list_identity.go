@@ -0,0 +1,68 @@
+package cilium
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// listedObject is the part of an object returned by a list data source which
+// contributes to its content hash. The resource version only contributes to
+// the ID, so that the hash stays the same when an object is rewritten
+// without changes.
+type listedObject struct {
+	Namespace   string            `json:"namespace,omitempty"`
+	Name        string            `json:"name"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Spec        interface{}       `json:"spec,omitempty"`
+	Status      interface{}       `json:"status,omitempty"`
+
+	resourceVersion string
+}
+
+func newListedObject(meta metav1.ObjectMeta, spec, status interface{}) listedObject {
+	return listedObject{
+		Namespace:       meta.Namespace,
+		Name:            meta.Name,
+		Labels:          meta.Labels,
+		Annotations:     meta.Annotations,
+		Spec:            spec,
+		Status:          status,
+		resourceVersion: meta.ResourceVersion,
+	}
+}
+
+// listIdentity returns the ID and content hash of the objects returned by a
+// list data source. The ID is derived from the cluster and the names and
+// resource versions of the objects, so it changes whenever an object is
+// added, removed or modified; the content hash only covers what the objects
+// contain. Both are independent of the order of objects.
+func listIdentity(cluster string, objects []listedObject) (id, contentHash string, err error) {
+	sorted := make([]listedObject, len(objects))
+	copy(sorted, objects)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Namespace != sorted[j].Namespace {
+			return sorted[i].Namespace < sorted[j].Namespace
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", cluster)
+	for _, o := range sorted {
+		fmt.Fprintf(h, "%s@%s\n", namespacedID(o.Namespace, o.Name), o.resourceVersion)
+	}
+	id = hex.EncodeToString(h.Sum(nil))
+
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return "", "", err
+	}
+	sum := sha256.Sum256(data)
+	return id, hex.EncodeToString(sum[:]), nil
+}
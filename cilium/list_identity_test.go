@@ -0,0 +1,52 @@
+package cilium
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestListIdentity(t *testing.T) {
+	node := func(name, resourceVersion, role string) listedObject {
+		return newListedObject(metav1.ObjectMeta{
+			Name:            name,
+			ResourceVersion: resourceVersion,
+			Labels:          map[string]string{"role": role},
+		}, nil, nil)
+	}
+	identity := func(cluster string, objects ...listedObject) (string, string) {
+		t.Helper()
+		id, contentHash, err := listIdentity(cluster, objects)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id, contentHash
+	}
+
+	id, contentHash := identity("https://a", node("n1", "1", "worker"), node("n2", "2", "worker"))
+
+	reorderedID, reorderedHash := identity("https://a", node("n2", "2", "worker"), node("n1", "1", "worker"))
+	if reorderedID != id || reorderedHash != contentHash {
+		t.Error("identity depends on the order of objects")
+	}
+
+	otherClusterID, otherClusterHash := identity("https://b", node("n1", "1", "worker"), node("n2", "2", "worker"))
+	if otherClusterID == id || otherClusterHash != contentHash {
+		t.Error("only the ID should depend on the cluster")
+	}
+
+	rewrittenID, rewrittenHash := identity("https://a", node("n1", "1", "worker"), node("n2", "3", "worker"))
+	if rewrittenID == id || rewrittenHash != contentHash {
+		t.Error("only the ID should depend on resource versions")
+	}
+
+	modifiedID, modifiedHash := identity("https://a", node("n1", "1", "worker"), node("n2", "3", "gateway"))
+	if modifiedID == id || modifiedHash == contentHash {
+		t.Error("modified content should change both the ID and the content hash")
+	}
+
+	removedID, removedHash := identity("https://a", node("n1", "1", "worker"))
+	if removedID == id || removedHash == contentHash {
+		t.Error("removing an object should change both the ID and the content hash")
+	}
+}
@@ -0,0 +1,179 @@
+package cilium
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	k8sjson "k8s.io/apimachinery/pkg/runtime/serializer/json"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// fieldManager is the field manager of the objects applied or written by the
+// provider, which tells its writes apart from those of the agents.
+const fieldManager = "terraform-provider-cilium"
+
+// decodeManifest parses a single YAML or JSON manifest. The manifest must
+// describe an object of a cilium.io kind registered in the Cilium scheme and
+// is decoded strictly into its typed form, so that misspelled fields are
+// reported before the object is sent to the API server.
+func decodeManifest(data string) (*unstructured.Unstructured, error) {
+	registerCiliumScheme()
+
+	jsonData, err := yaml.YAMLToJSON([]byte(data))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(jsonData); err != nil {
+		return nil, fmt.Errorf("unable to parse manifest: %w", err)
+	}
+
+	gvk := obj.GroupVersionKind()
+	if gvk.Group != ciliumv2.CustomResourceDefinitionGroup {
+		return nil, fmt.Errorf("%s is not a %s kind", obj.GetAPIVersion()+"/"+obj.GetKind(), ciliumv2.CustomResourceDefinitionGroup)
+	}
+	if !scheme.Scheme.Recognizes(gvk) {
+		return nil, fmt.Errorf("unknown kind %s in %s", gvk.Kind, gvk.GroupVersion())
+	}
+	if obj.GetName() == "" {
+		return nil, fmt.Errorf("metadata.name must be set")
+	}
+
+	serializer := k8sjson.NewSerializerWithOptions(k8sjson.DefaultMetaFactory, scheme.Scheme, scheme.Scheme, k8sjson.SerializerOptions{Strict: true})
+	if _, _, err := serializer.Decode(jsonData, nil, nil); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", gvk.Kind, err)
+	}
+
+	return obj, nil
+}
+
+// manifestID returns the Terraform ID of an object applied from a manifest,
+// in apiVersion/kind/namespace/name form. Cluster scoped objects omit the
+// namespace.
+func manifestID(gvk k8sschema.GroupVersionKind, namespace, name string) string {
+	parts := []string{gvk.GroupVersion().String(), gvk.Kind}
+	if namespace != "" {
+		parts = append(parts, namespace)
+	}
+	return strings.Join(append(parts, name), "/")
+}
+
+// parseManifestID reverses manifestID.
+func parseManifestID(id string) (gvk k8sschema.GroupVersionKind, namespace, name string, err error) {
+	parts := strings.Split(id, "/")
+	for _, part := range parts {
+		if part == "" {
+			parts = nil
+			break
+		}
+	}
+	switch len(parts) {
+	case 4:
+		name = parts[3]
+	case 5:
+		namespace, name = parts[3], parts[4]
+	default:
+		return gvk, "", "", fmt.Errorf("expected an ID of the form apiVersion/kind/namespace/name or apiVersion/kind/name, got %q", id)
+	}
+	gvk = k8sschema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]}
+	return gvk, namespace, name, nil
+}
+
+// projectFields returns the parts of live which are set in desired, so that
+// drift is only reported on the fields a manifest manages. Lists of the same
+// length are projected element by element; any other list is returned as is.
+// An empty map or list of desired which the server dropped is projected as is,
+// since the server does not keep empty values.
+func projectFields(desired, live interface{}) interface{} {
+	switch d := desired.(type) {
+	case map[string]interface{}:
+		l, ok := live.(map[string]interface{})
+		if !ok {
+			return live
+		}
+		projected := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			lv, ok := l[k]
+			switch {
+			case (!ok || lv == nil) && isEmptyValue(v):
+				projected[k] = v
+			case ok:
+				projected[k] = projectFields(v, lv)
+			}
+		}
+		return projected
+	case []interface{}:
+		l, ok := live.([]interface{})
+		if !ok || len(l) != len(d) {
+			return live
+		}
+		projected := make([]interface{}, len(d))
+		for i := range d {
+			projected[i] = projectFields(d[i], l[i])
+		}
+		return projected
+	}
+	return live
+}
+
+// isEmptyValue reports whether v is an empty map or list.
+func isEmptyValue(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// manifestEqual reports whether two decoded manifests are semantically
+// equal, regardless of key order and of the numeric types used.
+func manifestEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+// serverManagedMetadata lists the metadata fields set by the API server,
+// which are dropped when a live object is turned back into a manifest.
+var serverManagedMetadata = []string{
+	"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink",
+}
+
+// manifestFromLiveObject turns a live object into a manifest by dropping its
+// status and the metadata set by the API server.
+func manifestFromLiveObject(live *unstructured.Unstructured) map[string]interface{} {
+	obj := live.DeepCopy().Object
+	delete(obj, "status")
+	if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+		for _, field := range serverManagedMetadata {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+			delete(annotations, lastAppliedConfigAnnotation)
+			if len(annotations) == 0 {
+				delete(metadata, "annotations")
+			}
+		}
+	}
+	return obj
+}
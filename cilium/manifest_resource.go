@@ -0,0 +1,373 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                   = &ciliumManifestResource{}
+	_ resource.ResourceWithConfigure      = &ciliumManifestResource{}
+	_ resource.ResourceWithValidateConfig = &ciliumManifestResource{}
+	_ resource.ResourceWithModifyPlan     = &ciliumManifestResource{}
+	_ resource.ResourceWithImportState    = &ciliumManifestResource{}
+)
+
+// NewCiliumManifestResource is a helper function to simplify the provider implementation.
+func NewCiliumManifestResource() resource.Resource {
+	return &ciliumManifestResource{}
+}
+
+// ciliumManifestResourceModel maps the resource schema data.
+type ciliumManifestResourceModel struct {
+	ID         types.String `tfsdk:"id"`
+	YAMLBody   types.String `tfsdk:"yaml_body"`
+	Manifest   types.String `tfsdk:"manifest"`
+	APIVersion types.String `tfsdk:"api_version"`
+	Kind       types.String `tfsdk:"kind"`
+	Name       types.String `tfsdk:"name"`
+	Namespace  types.String `tfsdk:"namespace"`
+	UID        types.String `tfsdk:"uid"`
+}
+
+// body returns the configured manifest and the attribute holding it.
+func (m *ciliumManifestResourceModel) body() (types.String, path.Path) {
+	if !m.Manifest.IsNull() {
+		return m.Manifest, path.Root("manifest")
+	}
+	return m.YAMLBody, path.Root("yaml_body")
+}
+
+// ciliumManifestResource is the resource implementation.
+type ciliumManifestResource struct {
+	client *CiliumClient
+}
+
+// Configure adds the provider configured client to the resource.
+func (r *ciliumManifestResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*CiliumClient)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *CiliumClient, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *ciliumManifestResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_manifest"
+}
+
+// Schema defines the schema for the resource.
+func (r *ciliumManifestResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Applies a raw manifest of a cilium.io object with server-side apply. Drift is only tracked on the fields set in the manifest.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Description: "Identifier of the object in apiVersion/kind/namespace/name form, or apiVersion/kind/name for cluster scoped objects.",
+				Computed:    true,
+			},
+			"yaml_body": schema.StringAttribute{
+				Description: "YAML manifest of a single cilium.io object. Conflicts with manifest.",
+				Optional:    true,
+			},
+			"manifest": schema.StringAttribute{
+				Description: "JSON encoded manifest object of a single cilium.io object, usually written with jsonencode(). Conflicts with yaml_body.",
+				Optional:    true,
+			},
+			"api_version": schema.StringAttribute{
+				Description: "API version of the object.",
+				Computed:    true,
+			},
+			"kind": schema.StringAttribute{
+				Description: "Kind of the object.",
+				Computed:    true,
+			},
+			"name": schema.StringAttribute{
+				Description: "Name of the object.",
+				Computed:    true,
+			},
+			"namespace": schema.StringAttribute{
+				Description: "Namespace of the object, empty for cluster scoped objects.",
+				Computed:    true,
+			},
+			"uid": schema.StringAttribute{
+				Description: "Unique identifier assigned to the object by Kubernetes.",
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+// ValidateConfig checks that exactly one of yaml_body and manifest is set and
+// that it describes a cilium.io object.
+func (r *ciliumManifestResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config ciliumManifestResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if config.YAMLBody.IsNull() == config.Manifest.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("yaml_body"),
+			"Invalid Attribute Combination",
+			"Exactly one of yaml_body or manifest must be set.",
+		)
+		return
+	}
+
+	body, p := config.body()
+	if body.IsUnknown() {
+		return
+	}
+	if _, err := decodeManifest(body.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(p, "Invalid Manifest", err.Error())
+	}
+}
+
+// ModifyPlan fills the computed identity of the object from the manifest and
+// plans a replacement when the object the manifest describes changes.
+func (r *ciliumManifestResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ciliumManifestResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	body, p := plan.body()
+	if body.IsUnknown() {
+		return
+	}
+	obj, err := decodeManifest(body.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(p, "Invalid Manifest", err.Error())
+		return
+	}
+
+	plan.APIVersion = types.StringValue(obj.GetAPIVersion())
+	plan.Kind = types.StringValue(obj.GetKind())
+	plan.Name = types.StringValue(obj.GetName())
+	if obj.GetNamespace() != "" {
+		plan.Namespace = types.StringValue(obj.GetNamespace())
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state ciliumManifestResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if obj.GetNamespace() == "" {
+			plan.Namespace = state.Namespace
+		}
+		stateGVK, _, _, err := parseManifestID(state.ID.ValueString())
+		sameObject := err == nil &&
+			stateGVK.GroupKind() == obj.GroupVersionKind().GroupKind() &&
+			state.Name.Equal(plan.Name) &&
+			state.Namespace.Equal(plan.Namespace)
+		if !sameObject {
+			resp.RequiresReplace = append(resp.RequiresReplace, p)
+			plan.Namespace = stringValueOrUnknown(obj.GetNamespace())
+			plan.UID = types.StringUnknown()
+			plan.ID = types.StringUnknown()
+		} else if state.APIVersion.Equal(plan.APIVersion) {
+			plan.ID = state.ID
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *ciliumManifestResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ciliumManifestResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *ciliumManifestResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ciliumManifestResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	gvk, namespace, name, err := parseManifestID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Manifest ID", err.Error())
+		return
+	}
+
+	live, err := r.client.GetManifest(ctx, gvk, namespace, name)
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, "Object not found, removing it from the state", map[string]interface{}{"id": state.ID.ValueString()})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to Read Cilium Manifest", kubernetesErrorDetail(err))
+		return
+	}
+
+	body, p := state.body()
+	if body.IsNull() {
+		// The object was imported, the whole live object becomes the
+		// manifest.
+		data, err := yaml.Marshal(manifestFromLiveObject(live))
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to Encode Cilium Manifest", err.Error())
+			return
+		}
+		state.YAMLBody = types.StringValue(string(data))
+	} else {
+		desired, err := decodeManifest(body.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(p, "Invalid Manifest", err.Error())
+			return
+		}
+		projected := projectFields(desired.Object, live.Object)
+		if !manifestEqual(projected, desired.Object) {
+			tflog.Info(ctx, "Object drifted from its manifest", map[string]interface{}{"id": state.ID.ValueString()})
+			var data []byte
+			if p.Equal(path.Root("manifest")) {
+				data, err = json.Marshal(projected)
+			} else {
+				data, err = yaml.Marshal(projected)
+			}
+			if err != nil {
+				resp.Diagnostics.AddError("Unable to Encode Cilium Manifest", err.Error())
+				return
+			}
+			if p.Equal(path.Root("manifest")) {
+				state.Manifest = types.StringValue(string(data))
+			} else {
+				state.YAMLBody = types.StringValue(string(data))
+			}
+		}
+	}
+
+	setManifestIdentity(&state, live)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *ciliumManifestResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ciliumManifestResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.apply(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *ciliumManifestResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ciliumManifestResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	gvk, namespace, name, err := parseManifestID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Cilium Manifest ID", err.Error())
+		return
+	}
+
+	err = r.client.DeleteManifest(ctx, gvk, namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		resp.Diagnostics.AddError("Unable to Delete Cilium Manifest", kubernetesErrorDetail(err))
+	}
+}
+
+// ImportState imports an object by its apiVersion/kind/namespace/name, or
+// apiVersion/kind/name for cluster scoped objects.
+func (r *ciliumManifestResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if _, _, _, err := parseManifestID(req.ID); err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+}
+
+// apply applies the manifest of m and fills its computed attributes.
+func (r *ciliumManifestResource) apply(ctx context.Context, m *ciliumManifestResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	body, p := m.body()
+	obj, err := decodeManifest(body.ValueString())
+	if err != nil {
+		diags.AddAttributeError(p, "Invalid Manifest", err.Error())
+		return diags
+	}
+
+	applied, err := r.client.ApplyManifest(ctx, obj)
+	if err != nil {
+		diags.AddError("Unable to Apply Cilium Manifest", kubernetesErrorDetail(err))
+		return diags
+	}
+	tflog.Debug(ctx, "Applied manifest", map[string]interface{}{"kind": applied.GetKind(), "namespace": applied.GetNamespace(), "name": applied.GetName()})
+
+	setManifestIdentity(m, applied)
+	return diags
+}
+
+// setManifestIdentity sets the computed attributes of m from obj.
+func setManifestIdentity(m *ciliumManifestResourceModel, obj *unstructured.Unstructured) {
+	m.ID = types.StringValue(manifestID(obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName()))
+	m.APIVersion = types.StringValue(obj.GetAPIVersion())
+	m.Kind = types.StringValue(obj.GetKind())
+	m.Name = types.StringValue(obj.GetName())
+	m.Namespace = types.StringValue(obj.GetNamespace())
+	m.UID = types.StringValue(string(obj.GetUID()))
+}
+
+// stringValueOrUnknown returns an unknown value for an empty string.
+func stringValueOrUnknown(value string) types.String {
+	if value == "" {
+		return types.StringUnknown()
+	}
+	return types.StringValue(value)
+}
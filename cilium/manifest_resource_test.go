@@ -0,0 +1,72 @@
+package cilium
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCiliumManifestResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create and Read testing
+			{
+				Config: providerConfig + `
+resource "cilium_manifest" "test" {
+  yaml_body = <<-EOT
+    apiVersion: cilium.io/v2
+    kind: CiliumNetworkPolicy
+    metadata:
+      name: tf-acc-manifest
+    spec:
+      endpointSelector:
+        matchLabels:
+          app: test
+      egress:
+      - toFQDNs:
+        - matchName: api.twitter.com
+  EOT
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_manifest.test", "id", "cilium.io/v2/CiliumNetworkPolicy/default/tf-acc-manifest"),
+					resource.TestCheckResourceAttr("cilium_manifest.test", "kind", "CiliumNetworkPolicy"),
+					resource.TestCheckResourceAttr("cilium_manifest.test", "namespace", "default"),
+					resource.TestCheckResourceAttrSet("cilium_manifest.test", "uid"),
+				),
+			},
+			// ImportState testing
+			{
+				ResourceName:            "cilium_manifest.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"yaml_body"},
+			},
+			// Update from a manifest object, same object
+			{
+				Config: providerConfig + `
+resource "cilium_manifest" "test" {
+  manifest = jsonencode({
+    apiVersion = "cilium.io/v2"
+    kind       = "CiliumNetworkPolicy"
+    metadata = {
+      name = "tf-acc-manifest"
+    }
+    spec = {
+      endpointSelector = {
+        matchLabels = {
+          app = "test"
+        }
+      }
+    }
+  })
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("cilium_manifest.test", "id", "cilium.io/v2/CiliumNetworkPolicy/default/tf-acc-manifest"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,200 @@
+package cilium
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestDecodeManifest(t *testing.T) {
+	data, err := os.ReadFile("../examples/resources/cnp_fqdn.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	obj, err := decodeManifest(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if obj.GetKind() != "CiliumNetworkPolicy" || obj.GetNamespace() != "netpols" || obj.GetName() != "fqdn" {
+		t.Errorf("unexpected object %s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  string
+	}{
+		{
+			name:     "json",
+			manifest: `{"apiVersion": "cilium.io/v2", "kind": "CiliumClusterwideNetworkPolicy", "metadata": {"name": "test"}, "spec": {"endpointSelector": {}}}`,
+		},
+		{
+			name:     "not a cilium kind",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: test\n",
+			wantErr:  "is not a cilium.io kind",
+		},
+		{
+			name:     "unknown cilium kind",
+			manifest: "apiVersion: cilium.io/v2\nkind: CiliumFirewall\nmetadata:\n  name: test\n",
+			wantErr:  "unknown kind",
+		},
+		{
+			name:     "missing name",
+			manifest: "apiVersion: cilium.io/v2\nkind: CiliumNetworkPolicy\nmetadata: {}\n",
+			wantErr:  "metadata.name",
+		},
+		{
+			name:     "unknown field",
+			manifest: "apiVersion: cilium.io/v2\nkind: CiliumNetworkPolicy\nmetadata:\n  name: test\nspecification: {}\n",
+			wantErr:  "specification",
+		},
+		{
+			name:     "invalid yaml",
+			manifest: "apiVersion: [",
+			wantErr:  "unable to parse manifest",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeManifest(tt.manifest)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestManifestID(t *testing.T) {
+	cnp := k8sschema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumNetworkPolicy"}
+	ccnp := k8sschema.GroupVersionKind{Group: "cilium.io", Version: "v2", Kind: "CiliumClusterwideNetworkPolicy"}
+
+	tests := []struct {
+		id        string
+		gvk       k8sschema.GroupVersionKind
+		namespace string
+		name      string
+	}{
+		{"cilium.io/v2/CiliumNetworkPolicy/netpols/fqdn", cnp, "netpols", "fqdn"},
+		{"cilium.io/v2/CiliumClusterwideNetworkPolicy/mypod-ingress", ccnp, "", "mypod-ingress"},
+	}
+	for _, tt := range tests {
+		gvk, namespace, name, err := parseManifestID(tt.id)
+		if err != nil {
+			t.Fatalf("parseManifestID(%q): %v", tt.id, err)
+		}
+		if gvk != tt.gvk || namespace != tt.namespace || name != tt.name {
+			t.Errorf("parseManifestID(%q) = %v, %q, %q", tt.id, gvk, namespace, name)
+		}
+		if id := manifestID(gvk, namespace, name); id != tt.id {
+			t.Errorf("manifestID() = %q, want %q", id, tt.id)
+		}
+	}
+
+	for _, id := range []string{"", "fqdn", "cilium.io/v2/CiliumNetworkPolicy", "cilium.io/v2/CiliumNetworkPolicy//fqdn", "a/b/c/d/e/f"} {
+		if _, _, _, err := parseManifestID(id); err == nil {
+			t.Errorf("parseManifestID(%q) did not fail", id)
+		}
+	}
+}
+
+func TestProjectFields(t *testing.T) {
+	desired, err := decodeManifest(`
+apiVersion: cilium.io/v2
+kind: CiliumNetworkPolicy
+metadata:
+  name: test
+  labels:
+    team: one
+spec:
+  endpointSelector:
+    matchLabels:
+      app: web
+  egress:
+  - toPorts:
+    - ports:
+      - port: "53"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	live := desired.DeepCopy()
+	live.SetNamespace("default")
+	live.SetUID("0b7c2a59")
+	live.SetLabels(map[string]string{"team": "one", "added-by": "controller"})
+	live.Object["status"] = map[string]interface{}{"nodes": map[string]interface{}{}}
+
+	if !manifestEqual(projectFields(desired.Object, live.Object), desired.Object) {
+		t.Errorf("fields not set in the manifest are reported as drift")
+	}
+
+	if err := unstructured.SetNestedField(live.Object, "one-two", "metadata", "labels", "team"); err != nil {
+		t.Fatal(err)
+	}
+	if manifestEqual(projectFields(desired.Object, live.Object), desired.Object) {
+		t.Errorf("changed label is not reported as drift")
+	}
+
+	live = desired.DeepCopy()
+	if err := unstructured.SetNestedSlice(live.Object, []interface{}{}, "spec", "egress"); err != nil {
+		t.Fatal(err)
+	}
+	if manifestEqual(projectFields(desired.Object, live.Object), desired.Object) {
+		t.Errorf("removed egress rule is not reported as drift")
+	}
+
+	// The server drops empty values, which must not be reported as drift.
+	if err := unstructured.SetNestedField(desired.Object, map[string]interface{}{}, "metadata", "annotations"); err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedSlice(desired.Object, []interface{}{}, "spec", "ingress"); err != nil {
+		t.Fatal(err)
+	}
+	live = desired.DeepCopy()
+	unstructured.RemoveNestedField(live.Object, "metadata", "annotations")
+	unstructured.RemoveNestedField(live.Object, "spec", "ingress")
+	if !manifestEqual(projectFields(desired.Object, live.Object), desired.Object) {
+		t.Errorf("empty values dropped by the server are reported as drift")
+	}
+}
+
+func TestManifestFromLiveObject(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "cilium.io/v2",
+		"kind":       "CiliumNetworkPolicy",
+		"metadata": map[string]interface{}{
+			"name":              "fqdn",
+			"namespace":         "netpols",
+			"uid":               "0b7c2a59",
+			"resourceVersion":   "42",
+			"generation":        int64(1),
+			"creationTimestamp": "2023-04-01T00:00:00Z",
+			"managedFields":     []interface{}{},
+			"annotations": map[string]interface{}{
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+			},
+		},
+		"spec":   map[string]interface{}{"endpointSelector": map[string]interface{}{}},
+		"status": map[string]interface{}{},
+	}}
+
+	want := map[string]interface{}{
+		"apiVersion": "cilium.io/v2",
+		"kind":       "CiliumNetworkPolicy",
+		"metadata":   map[string]interface{}{"name": "fqdn", "namespace": "netpols"},
+		"spec":       map[string]interface{}{"endpointSelector": map[string]interface{}{}},
+	}
+	if got := manifestFromLiveObject(live); !manifestEqual(got, want) {
+		t.Errorf("manifestFromLiveObject() = %v, want %v", got, want)
+	}
+}
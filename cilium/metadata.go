@@ -0,0 +1,269 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	dsschema "github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// defaultNamespace is used for namespaced objects which do not set one.
+const defaultNamespace = "default"
+
+// lastAppliedConfigAnnotation is set by kubectl apply. It is not reported in
+// the state of resources, so that objects applied with kubectl can be
+// imported without copying their whole manifest into an annotation.
+const lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// objectMetaModel maps the metadata of a namespaced object managed by a
+// resource.
+type objectMetaModel struct {
+	Name        types.String      `tfsdk:"name"`
+	Namespace   types.String      `tfsdk:"namespace"`
+	Labels      map[string]string `tfsdk:"labels"`
+	Annotations map[string]string `tfsdk:"annotations"`
+	UID         types.String      `tfsdk:"uid"`
+}
+
+// clusterObjectMetaModel maps the metadata of a cluster scoped object managed
+// by a resource.
+type clusterObjectMetaModel struct {
+	Name        types.String      `tfsdk:"name"`
+	Labels      map[string]string `tfsdk:"labels"`
+	Annotations map[string]string `tfsdk:"annotations"`
+	UID         types.String      `tfsdk:"uid"`
+}
+
+// metadataAttribute returns the schema of the metadata of an object. The
+// namespace attribute is only present for namespaced objects.
+func metadataAttribute(namespaced bool) schema.SingleNestedAttribute {
+	attributes := map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Description: "Name of the object. Changing it forces a new object to be created.",
+			Required:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"labels": schema.MapAttribute{
+			Description: "Kubernetes labels of the object.",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"annotations": schema.MapAttribute{
+			Description: "Kubernetes annotations of the object.",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"uid": schema.StringAttribute{
+			Description: "Unique identifier assigned to the object by Kubernetes.",
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+	}
+	if namespaced {
+		attributes["namespace"] = schema.StringAttribute{
+			Description: "Namespace of the object. Defaults to \"default\". Changing it forces a new object to be created.",
+			Optional:    true,
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				defaultStringValue(defaultNamespace),
+				stringplanmodifier.RequiresReplace(),
+			},
+		}
+	}
+
+	return schema.SingleNestedAttribute{
+		Description: "Standard Kubernetes object metadata.",
+		Required:    true,
+		Attributes:  attributes,
+	}
+}
+
+func (m *objectMetaModel) objectMeta() metav1.ObjectMeta {
+	namespace := m.Namespace.ValueString()
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return metav1.ObjectMeta{
+		Name:        m.Name.ValueString(),
+		Namespace:   namespace,
+		Labels:      m.Labels,
+		Annotations: m.Annotations,
+	}
+}
+
+func flattenObjectMeta(meta metav1.ObjectMeta) *objectMetaModel {
+	return &objectMetaModel{
+		Name:        types.StringValue(meta.Name),
+		Namespace:   types.StringValue(meta.Namespace),
+		Labels:      nilIfEmptyMap(meta.Labels),
+		Annotations: flattenAnnotations(meta.Annotations),
+		UID:         types.StringValue(string(meta.UID)),
+	}
+}
+
+func (m *clusterObjectMetaModel) objectMeta() metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        m.Name.ValueString(),
+		Labels:      m.Labels,
+		Annotations: m.Annotations,
+	}
+}
+
+func flattenClusterObjectMeta(meta metav1.ObjectMeta) *clusterObjectMetaModel {
+	return &clusterObjectMetaModel{
+		Name:        types.StringValue(meta.Name),
+		Labels:      nilIfEmptyMap(meta.Labels),
+		Annotations: flattenAnnotations(meta.Annotations),
+		UID:         types.StringValue(string(meta.UID)),
+	}
+}
+
+// flattenAnnotations returns the annotations of an object without the one
+// set by kubectl apply.
+func flattenAnnotations(annotations map[string]string) map[string]string {
+	if _, ok := annotations[lastAppliedConfigAnnotation]; !ok {
+		return nilIfEmptyMap(annotations)
+	}
+	flattened := make(map[string]string, len(annotations)-1)
+	for k, v := range annotations {
+		if k != lastAppliedConfigAnnotation {
+			flattened[k] = v
+		}
+	}
+	return nilIfEmptyMap(flattened)
+}
+
+// dataSourceObjectMetaModel maps the metadata of an object read by a data
+// source.
+type dataSourceObjectMetaModel struct {
+	Name              types.String      `tfsdk:"name"`
+	Namespace         types.String      `tfsdk:"namespace"`
+	Labels            map[string]string `tfsdk:"labels"`
+	Annotations       map[string]string `tfsdk:"annotations"`
+	UID               types.String      `tfsdk:"uid"`
+	ResourceVersion   types.String      `tfsdk:"resource_version"`
+	Generation        types.Int64       `tfsdk:"generation"`
+	CreationTimestamp types.String      `tfsdk:"creation_timestamp"`
+}
+
+// dataSourceMetadataAttribute returns the schema of the metadata of an object
+// read by a data source.
+func dataSourceMetadataAttribute() dsschema.SingleNestedAttribute {
+	return dsschema.SingleNestedAttribute{
+		Description: "Standard Kubernetes object metadata.",
+		Computed:    true,
+		Attributes: map[string]dsschema.Attribute{
+			"name": dsschema.StringAttribute{
+				Description: "Name of the object.",
+				Computed:    true,
+			},
+			"namespace": dsschema.StringAttribute{
+				Description: "Namespace of the object, empty for cluster scoped objects.",
+				Computed:    true,
+			},
+			"labels": dsschema.MapAttribute{
+				Description: "Kubernetes labels of the object.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"annotations": dsschema.MapAttribute{
+				Description: "Kubernetes annotations of the object.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"uid": dsschema.StringAttribute{
+				Description: "Unique identifier assigned to the object by Kubernetes.",
+				Computed:    true,
+			},
+			"resource_version": dsschema.StringAttribute{
+				Description: "Version of the object, changed by every update.",
+				Computed:    true,
+			},
+			"generation": dsschema.Int64Attribute{
+				Description: "Generation of the desired state of the object.",
+				Computed:    true,
+			},
+			"creation_timestamp": dsschema.StringAttribute{
+				Description: "Creation time of the object in RFC 3339 format.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func flattenDataSourceObjectMeta(meta metav1.ObjectMeta) *dataSourceObjectMetaModel {
+	m := &dataSourceObjectMetaModel{
+		Name:            types.StringValue(meta.Name),
+		Namespace:       types.StringValue(meta.Namespace),
+		Labels:          nilIfEmptyMap(meta.Labels),
+		Annotations:     nilIfEmptyMap(meta.Annotations),
+		UID:             types.StringValue(string(meta.UID)),
+		ResourceVersion: types.StringValue(meta.ResourceVersion),
+		Generation:      types.Int64Value(meta.Generation),
+	}
+	if !meta.CreationTimestamp.IsZero() {
+		m.CreationTimestamp = types.StringValue(meta.CreationTimestamp.UTC().Format(time.RFC3339))
+	}
+	return m
+}
+
+// nilIfEmptyMap maps an empty map to nil so that it is stored as null.
+func nilIfEmptyMap(values map[string]string) map[string]string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// namespacedID returns the Terraform ID of a namespaced object.
+func namespacedID(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// parseNamespacedID splits an ID in namespace/name form. A bare name refers
+// to an object in the default namespace.
+func parseNamespacedID(id string) (namespace, name string, err error) {
+	parts := strings.Split(id, "/")
+	switch {
+	case len(parts) == 1 && parts[0] != "":
+		return defaultNamespace, parts[0], nil
+	case len(parts) == 2 && parts[0] != "" && parts[1] != "":
+		return parts[0], parts[1], nil
+	}
+	return "", "", fmt.Errorf("expected an ID of the form namespace/name, got %q", id)
+}
+
+// defaultStringValue returns a plan modifier which sets the planned value to
+// value when the attribute is not configured.
+func defaultStringValue(value string) planmodifier.String {
+	return defaultStringModifier{value: value}
+}
+
+type defaultStringModifier struct {
+	value string
+}
+
+func (m defaultStringModifier) Description(_ context.Context) string {
+	return fmt.Sprintf("Defaults to %q.", m.value)
+}
+
+func (m defaultStringModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m defaultStringModifier) PlanModifyString(_ context.Context, req planmodifier.StringRequest, resp *planmodifier.StringResponse) {
+	if req.ConfigValue.IsNull() {
+		resp.PlanValue = types.StringValue(m.value)
+	}
+}
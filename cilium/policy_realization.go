@@ -0,0 +1,187 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+const defaultRealizationTimeout = 2 * time.Minute
+
+// realizationPollInterval is how often the status of a policy is read while
+// waiting for its realization.
+var realizationPollInterval = 2 * time.Second
+
+// waitForRealizationModel maps the wait_for_realization attribute of the
+// policy resources.
+type waitForRealizationModel struct {
+	Timeout         types.String  `tfsdk:"timeout"`
+	MinNodeFraction types.Float64 `tfsdk:"min_node_fraction"`
+}
+
+func waitForRealizationAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Wait after each apply until the Cilium agents report the policy as imported and enforced. " +
+			"The apply fails with the errors reported by the agents if any of them fails to import the policy. " +
+			"Requires the agents to report policy status, which Cilium disables by default: " +
+			"set the `enableCnpStatusUpdates` Helm value, or the apply times out.",
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"timeout": schema.StringAttribute{
+				Description: fmt.Sprintf("How long to wait, as a Go duration such as `5m`. Defaults to `%s`.", defaultRealizationTimeout),
+				Optional:    true,
+			},
+			"min_node_fraction": schema.Float64Attribute{
+				Description: "Fraction of the CiliumNodes of the cluster, between 0 and 1, which must enforce the policy. Defaults to 1.",
+				Optional:    true,
+			},
+		},
+	}
+}
+
+// realizationOptions returns the timeout and node fraction configured in m.
+func (m *waitForRealizationModel) realizationOptions() (time.Duration, float64, error) {
+	timeout, fraction := defaultRealizationTimeout, 1.0
+	if !m.Timeout.IsNull() {
+		var err error
+		if timeout, err = time.ParseDuration(m.Timeout.ValueString()); err != nil {
+			return 0, 0, fmt.Errorf("invalid timeout: %w", err)
+		}
+		if timeout <= 0 {
+			return 0, 0, fmt.Errorf("timeout must be positive, got %s", m.Timeout.ValueString())
+		}
+	}
+	if !m.MinNodeFraction.IsNull() {
+		fraction = m.MinNodeFraction.ValueFloat64()
+		if fraction < 0 || fraction > 1 {
+			return 0, 0, fmt.Errorf("min_node_fraction must be between 0 and 1, got %g", fraction)
+		}
+	}
+	return timeout, fraction, nil
+}
+
+// validateWaitForRealization checks the wait_for_realization attribute of a
+// policy resource configuration.
+func validateWaitForRealization(ctx context.Context, config tfsdk.Config) diag.Diagnostics {
+	var diags diag.Diagnostics
+	p := path.Root("wait_for_realization")
+
+	var m waitForRealizationModel
+	diags.Append(config.GetAttribute(ctx, p.AtName("timeout"), &m.Timeout)...)
+	diags.Append(config.GetAttribute(ctx, p.AtName("min_node_fraction"), &m.MinNodeFraction)...)
+	if diags.HasError() || m.Timeout.IsUnknown() || m.MinNodeFraction.IsUnknown() {
+		return diags
+	}
+	if _, _, err := m.realizationOptions(); err != nil {
+		diags.AddAttributeError(p, "Invalid Wait For Realization", err.Error())
+	}
+	return diags
+}
+
+// waitForPolicyRealization polls the status of a policy until enough agents
+// report it as imported and enforced. Only the statuses updated since the
+// policy was last modified are considered, so that an agent still enforcing
+// the previous rules does not count. It fails as soon as an agent reports an
+// error.
+func waitForPolicyRealization(ctx context.Context, m *waitForRealizationModel, since time.Time, countNodes func(context.Context) (int, error), getStatus func(context.Context) (ciliumv2.CiliumNetworkPolicyStatus, error)) error {
+	timeout, fraction, err := m.realizationOptions()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	nodes, err := countNodes(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to count the CiliumNodes of the cluster: %w", err)
+	}
+	required := int(math.Ceil(fraction * float64(nodes)))
+
+	timedOut := func(realized, reported int) error {
+		err := fmt.Errorf("timed out after %s: the policy is enforced on %d of %d nodes, %d required", timeout, realized, nodes, required)
+		if reported == 0 {
+			return fmt.Errorf("%w. No agent reported the status of the policy: the agents may not be reporting policy status, "+
+				"which requires the enableCnpStatusUpdates Helm value", err)
+		}
+		return err
+	}
+
+	ticker := time.NewTicker(realizationPollInterval)
+	defer ticker.Stop()
+	realized, reported := 0, 0
+	for {
+		status, err := getStatus(ctx)
+		if ctx.Err() != nil {
+			return timedOut(realized, reported)
+		}
+		if err != nil {
+			return fmt.Errorf("unable to read the status of the policy: %w", err)
+		}
+
+		realized, reported = 0, 0
+		var failed []string
+		for node, s := range status.Nodes {
+			if s.LastUpdated.Time.Before(since) {
+				continue
+			}
+			reported++
+			switch {
+			case s.Error != "":
+				failed = append(failed, fmt.Sprintf("%s: %s", node, s.Error))
+			case s.OK && s.Enforcing:
+				realized++
+			}
+		}
+		if len(failed) > 0 {
+			sort.Strings(failed)
+			return fmt.Errorf("%d agent(s) failed to import the policy:\n%s", len(failed), strings.Join(failed, "\n"))
+		}
+		tflog.Debug(ctx, "Waiting for policy realization", map[string]interface{}{"realized": realized, "required": required, "nodes": nodes})
+		if realized >= required {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return timedOut(realized, reported)
+		case <-ticker.C:
+		}
+	}
+}
+
+// lastModified returns when the provider last wrote an object, according to
+// the API server, truncated to the second precision of policy statuses. Only
+// the managed fields of the provider are considered: the agents write the
+// status of a policy too, which must not be mistaken for a new revision.
+func lastModified(meta metav1.ObjectMeta) time.Time {
+	t := meta.CreationTimestamp.Time
+	for _, entry := range meta.ManagedFields {
+		if entry.Manager == fieldManager && entry.Subresource == "" && entry.Time != nil && entry.Time.After(t) {
+			t = entry.Time.Time
+		}
+	}
+	return t.Truncate(time.Second)
+}
+
+// countCiliumNodes returns the number of CiliumNodes, i.e. of Cilium agents,
+// in the cluster.
+func (c *CiliumClient) countCiliumNodes(ctx context.Context) (int, error) {
+	nodes, err := c.ListCiliumNodes(ctx, metav1.ListOptions{Limit: defaultCiliumNodePageSize})
+	if err != nil {
+		return 0, err
+	}
+	return len(nodes.Items), nil
+}
@@ -0,0 +1,142 @@
+package cilium
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+)
+
+func TestWaitForPolicyRealization(t *testing.T) {
+	defer func(interval time.Duration) { realizationPollInterval = interval }(realizationPollInterval)
+	realizationPollInterval = 10 * time.Millisecond
+
+	since := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fresh := slimv1.NewTime(since.Add(time.Second))
+	stale := slimv1.NewTime(since.Add(-time.Second))
+	threeNodes := func(context.Context) (int, error) { return 3, nil }
+	wait := func(m *waitForRealizationModel, statuses ...map[string]ciliumv2.CiliumNetworkPolicyNodeStatus) error {
+		polls := 0
+		return waitForPolicyRealization(context.Background(), m, since, threeNodes, func(context.Context) (ciliumv2.CiliumNetworkPolicyStatus, error) {
+			nodes := statuses[len(statuses)-1]
+			if polls < len(statuses) {
+				nodes = statuses[polls]
+			}
+			polls++
+			return ciliumv2.CiliumNetworkPolicyStatus{Nodes: nodes}, nil
+		})
+	}
+	enforced := ciliumv2.CiliumNetworkPolicyNodeStatus{OK: true, Enforcing: true, LastUpdated: fresh}
+
+	t.Run("all nodes", func(t *testing.T) {
+		err := wait(&waitForRealizationModel{Timeout: types.StringValue("5s"), MinNodeFraction: types.Float64Null()},
+			map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{"n1": enforced},
+			map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{"n1": enforced, "n2": enforced, "n3": enforced},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("fraction", func(t *testing.T) {
+		err := wait(&waitForRealizationModel{Timeout: types.StringNull(), MinNodeFraction: types.Float64Value(0.5)},
+			map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{"n1": enforced, "n2": enforced},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("agent errors", func(t *testing.T) {
+		err := wait(&waitForRealizationModel{Timeout: types.StringValue("5s"), MinNodeFraction: types.Float64Null()},
+			map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{
+				"n1": enforced,
+				"n3": {Error: "invalid rule", LastUpdated: fresh},
+				"n2": {Error: "unknown port", LastUpdated: fresh},
+			},
+		)
+		want := "2 agent(s) failed to import the policy:\nn2: unknown port\nn3: invalid rule"
+		if err == nil || err.Error() != want {
+			t.Fatalf("got error %v, want %q", err, want)
+		}
+	})
+
+	t.Run("stale statuses", func(t *testing.T) {
+		err := wait(&waitForRealizationModel{Timeout: types.StringValue("100ms"), MinNodeFraction: types.Float64Null()},
+			map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{
+				"n1": enforced,
+				"n2": {OK: true, Enforcing: true, LastUpdated: stale},
+				"n3": {Error: "previous revision failed", LastUpdated: stale},
+			},
+		)
+		want := "timed out after 100ms: the policy is enforced on 1 of 3 nodes, 3 required"
+		if err == nil || err.Error() != want {
+			t.Fatalf("got error %v, want %q", err, want)
+		}
+	})
+
+	t.Run("no status reported", func(t *testing.T) {
+		err := wait(&waitForRealizationModel{Timeout: types.StringValue("100ms"), MinNodeFraction: types.Float64Null()},
+			map[string]ciliumv2.CiliumNetworkPolicyNodeStatus{
+				"n1": {OK: true, Enforcing: true, LastUpdated: stale},
+			},
+		)
+		if err == nil || !strings.Contains(err.Error(), "enforced on 0 of 3 nodes") || !strings.Contains(err.Error(), "may not be reporting policy status") {
+			t.Fatalf("got error %v, want a hint about the status updates of the agents", err)
+		}
+	})
+}
+
+func TestWaitForRealizationOptions(t *testing.T) {
+	for _, tc := range []struct {
+		timeout  types.String
+		fraction types.Float64
+		err      string
+	}{
+		{timeout: types.StringNull(), fraction: types.Float64Null()},
+		{timeout: types.StringValue("30s"), fraction: types.Float64Value(0)},
+		{timeout: types.StringValue("soon"), fraction: types.Float64Null(), err: "invalid timeout"},
+		{timeout: types.StringValue("-1m"), fraction: types.Float64Null(), err: "timeout must be positive"},
+		{timeout: types.StringNull(), fraction: types.Float64Value(1.5), err: "min_node_fraction must be between 0 and 1"},
+	} {
+		m := &waitForRealizationModel{Timeout: tc.timeout, MinNodeFraction: tc.fraction}
+		_, _, err := m.realizationOptions()
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s, %s: unexpected error %v", tc.timeout, tc.fraction, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s, %s: got error %v, want %q", tc.timeout, tc.fraction, err, tc.err)
+		}
+	}
+}
+
+func TestLastModified(t *testing.T) {
+	created := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	updated := metav1.NewTime(created.Add(90*time.Second + 500*time.Millisecond))
+	reported := metav1.NewTime(created.Add(2 * time.Minute))
+	meta := metav1.ObjectMeta{
+		CreationTimestamp: metav1.NewTime(created),
+		ManagedFields: []metav1.ManagedFieldsEntry{
+			{Manager: fieldManager, Operation: metav1.ManagedFieldsOperationUpdate, Time: &updated},
+			{Manager: "cilium-agent", Operation: metav1.ManagedFieldsOperationUpdate, Time: &reported},
+		},
+	}
+	if got, want := lastModified(meta), created.Add(90*time.Second); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// A status reported by an agent for an earlier revision, after the policy
+	// was created, is not a write of the provider.
+	meta.ManagedFields = []metav1.ManagedFieldsEntry{
+		{Manager: "cilium-agent", Operation: metav1.ManagedFieldsOperationUpdate, Subresource: "status", Time: &reported},
+	}
+	if got := lastModified(meta); !got.Equal(created) {
+		t.Errorf("got %s, want the creation time %s", got, created)
+	}
+}
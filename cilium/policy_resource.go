@@ -0,0 +1,292 @@
+package cilium
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cilium/cilium/pkg/defaults"
+	"github.com/cilium/cilium/pkg/fqdn/re"
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// policyClient reads and writes the policies of one kind for the policy
+// resources. CiliumClusterwideNetworkPolicies carry the same fields as
+// CiliumNetworkPolicies and are handled as such, without namespace.
+type policyClient struct {
+	// kind is the Kubernetes kind of the policies, used in logs.
+	kind string
+	// title names the policies in diagnostics.
+	title string
+
+	get    func(ctx context.Context, namespace, name string) (*ciliumv2.CiliumNetworkPolicy, error)
+	create func(ctx context.Context, policy *ciliumv2.CiliumNetworkPolicy) (*ciliumv2.CiliumNetworkPolicy, error)
+	update func(ctx context.Context, namespace, name string, mutate func(*ciliumv2.CiliumNetworkPolicy)) (*ciliumv2.CiliumNetworkPolicy, error)
+	delete func(ctx context.Context, namespace, name string) error
+
+	countNodes func(ctx context.Context) (int, error)
+}
+
+// networkPolicyClient returns the policyClient of CiliumNetworkPolicies.
+func networkPolicyClient(c *CiliumClient) policyClient {
+	return policyClient{
+		kind:       ciliumv2.CNPKindDefinition,
+		title:      "Cilium Network Policy",
+		get:        c.GetCiliumNetworkPolicy,
+		create:     c.CreateCiliumNetworkPolicy,
+		update:     c.UpdateCiliumNetworkPolicy,
+		delete:     c.DeleteCiliumNetworkPolicy,
+		countNodes: c.countCiliumNodes,
+	}
+}
+
+// clusterwideNetworkPolicyClient returns the policyClient of
+// CiliumClusterwideNetworkPolicies.
+func clusterwideNetworkPolicyClient(c *CiliumClient) policyClient {
+	return policyClient{
+		kind:  ciliumv2.CCNPKindDefinition,
+		title: "Cilium Clusterwide Network Policy",
+		get: func(ctx context.Context, _, name string) (*ciliumv2.CiliumNetworkPolicy, error) {
+			ccnp, err := c.GetCiliumClusterwideNetworkPolicy(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			return clusterwideAsNetworkPolicy(ccnp), nil
+		},
+		create: func(ctx context.Context, policy *ciliumv2.CiliumNetworkPolicy) (*ciliumv2.CiliumNetworkPolicy, error) {
+			created, err := c.CreateCiliumClusterwideNetworkPolicy(ctx, &ciliumv2.CiliumClusterwideNetworkPolicy{
+				ObjectMeta: policy.ObjectMeta,
+				Spec:       policy.Spec,
+				Specs:      policy.Specs,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return clusterwideAsNetworkPolicy(created), nil
+		},
+		update: func(ctx context.Context, _, name string, mutate func(*ciliumv2.CiliumNetworkPolicy)) (*ciliumv2.CiliumNetworkPolicy, error) {
+			updated, err := c.UpdateCiliumClusterwideNetworkPolicy(ctx, name, func(ccnp *ciliumv2.CiliumClusterwideNetworkPolicy) {
+				policy := clusterwideAsNetworkPolicy(ccnp)
+				mutate(policy)
+				ccnp.ObjectMeta, ccnp.Spec, ccnp.Specs = policy.ObjectMeta, policy.Spec, policy.Specs
+			})
+			if err != nil {
+				return nil, err
+			}
+			return clusterwideAsNetworkPolicy(updated), nil
+		},
+		delete: func(ctx context.Context, _, name string) error {
+			return c.DeleteCiliumClusterwideNetworkPolicy(ctx, name)
+		},
+		countNodes: c.countCiliumNodes,
+	}
+}
+
+// clusterwideAsNetworkPolicy returns the fields of a
+// CiliumClusterwideNetworkPolicy as a CiliumNetworkPolicy.
+func clusterwideAsNetworkPolicy(ccnp *ciliumv2.CiliumClusterwideNetworkPolicy) *ciliumv2.CiliumNetworkPolicy {
+	return &ciliumv2.CiliumNetworkPolicy{
+		ObjectMeta: ccnp.ObjectMeta,
+		Spec:       ccnp.Spec,
+		Specs:      ccnp.Specs,
+		Status:     ccnp.Status,
+	}
+}
+
+// policyID identifies a policy in diagnostics and logs.
+func policyID(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespacedID(namespace, name)
+}
+
+// policyAttributes returns the schema attributes of the policy resources
+// other than their metadata.
+func policyAttributes(idDescription string) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"id": schema.StringAttribute{
+			Description: idDescription,
+			Computed:    true,
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"spec": schema.SingleNestedAttribute{
+			Description: "The policy rule.",
+			Optional:    true,
+			Attributes:  ruleAttributes(),
+		},
+		"specs": schema.ListNestedAttribute{
+			Description: "A list of policy rules, for policies made of several rules.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: ruleAttributes(),
+			},
+		},
+		"wait_for_realization": waitForRealizationAttribute(),
+		"status":               policyStatusAttribute(),
+	}
+}
+
+// createPolicy creates a policy with meta and the given rules, adding an
+// error to diags and returning nil on failure.
+func (p policyClient) createPolicy(ctx context.Context, meta metav1.ObjectMeta, spec *ruleModel, specs []ruleModel, diags *diag.Diagnostics) *ciliumv2.CiliumNetworkPolicy {
+	policy := &ciliumv2.CiliumNetworkPolicy{ObjectMeta: meta}
+	var err error
+	if policy.Spec, policy.Specs, err = expandPolicyRules(spec, specs); err != nil {
+		diags.AddError("Invalid "+p.title, err.Error())
+		return nil
+	}
+
+	created, err := p.create(ctx, policy)
+	if err != nil {
+		diags.AddError("Unable to Create "+p.title, kubernetesErrorDetail(err))
+		return nil
+	}
+	tflog.Debug(ctx, "Created "+p.kind, map[string]interface{}{"id": policyID(created.Namespace, created.Name)})
+	return created
+}
+
+// readPolicy reads a policy, returning nil when it no longer exists or on
+// failure, in which case an error is added to diags. imported tells that
+// the state comes from an import, and warns about the fields of the policy
+// the resource cannot hold.
+func (p policyClient) readPolicy(ctx context.Context, namespace, name string, imported bool, diags *diag.Diagnostics) *ciliumv2.CiliumNetworkPolicy {
+	policy, err := p.get(ctx, namespace, name)
+	if apierrors.IsNotFound(err) {
+		tflog.Warn(ctx, p.kind+" not found, removing it from the state", map[string]interface{}{"id": policyID(namespace, name)})
+		return nil
+	}
+	if err != nil {
+		diags.AddError("Unable to Read "+p.title, kubernetesErrorDetail(err))
+		return nil
+	}
+
+	if imported {
+		if err := checkRulesImportable(policy.Spec, policy.Specs); err != nil {
+			diags.AddWarning(
+				p.title+" Not Fully Imported",
+				fmt.Sprintf("Policy %q has %s. These fields will be removed when the policy is next applied; "+
+					"manage the policy with cilium_manifest to keep them.", policyID(namespace, name), err),
+			)
+		}
+	}
+	return policy
+}
+
+// updatePolicy replaces the labels, annotations and rules of a policy,
+// adding an error to diags and returning nil on failure.
+func (p policyClient) updatePolicy(ctx context.Context, meta metav1.ObjectMeta, spec *ruleModel, specs []ruleModel, diags *diag.Diagnostics) *ciliumv2.CiliumNetworkPolicy {
+	rule, rules, err := expandPolicyRules(spec, specs)
+	if err != nil {
+		diags.AddError("Invalid "+p.title, err.Error())
+		return nil
+	}
+
+	updated, err := p.update(ctx, meta.Namespace, meta.Name, func(policy *ciliumv2.CiliumNetworkPolicy) {
+		policy.Labels = meta.Labels
+		policy.Annotations = meta.Annotations
+		policy.Spec, policy.Specs = rule, rules
+	})
+	if err != nil {
+		diags.AddError("Unable to Update "+p.title, kubernetesErrorDetail(err))
+		return nil
+	}
+	return updated
+}
+
+// deletePolicy deletes a policy, ignoring policies which no longer exist.
+func (p policyClient) deletePolicy(ctx context.Context, namespace, name string, diags *diag.Diagnostics) {
+	err := p.delete(ctx, namespace, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		diags.AddError("Unable to Delete "+p.title, kubernetesErrorDetail(err))
+	}
+}
+
+// waitForRealization waits for the agents to enforce policy if configured
+// to, adding an error to diags if they do not. The state is set beforehand,
+// so that the policy is tracked, and tainted when just created. The status
+// in state is then replaced with the last one read.
+func (p policyClient) waitForRealization(ctx context.Context, wait *waitForRealizationModel, policy *ciliumv2.CiliumNetworkPolicy, state *tfsdk.State, diags *diag.Diagnostics) {
+	if wait == nil || diags.HasError() {
+		return
+	}
+	var last *ciliumv2.CiliumNetworkPolicyStatus
+	err := waitForPolicyRealization(ctx, wait, lastModified(policy.ObjectMeta), p.countNodes, func(ctx context.Context) (ciliumv2.CiliumNetworkPolicyStatus, error) {
+		current, err := p.get(ctx, policy.Namespace, policy.Name)
+		if err != nil {
+			return ciliumv2.CiliumNetworkPolicyStatus{}, err
+		}
+		last = &current.Status
+		return current.Status, nil
+	})
+	if last != nil {
+		status, d := policyStatusObject(ctx, *last)
+		diags.Append(d...)
+		diags.Append(state.SetAttribute(ctx, path.Root("status"), status)...)
+	}
+	if err != nil {
+		diags.AddError(p.title+" Not Realized", err.Error())
+	}
+}
+
+// flattenPolicy converts the rules and status of a policy into the
+// attributes shared by the policy resources.
+func flattenPolicy(ctx context.Context, policy *ciliumv2.CiliumNetworkPolicy) (*ruleModel, []ruleModel, types.Object, diag.Diagnostics) {
+	var specs []ruleModel
+	for _, rule := range policy.Specs {
+		specs = append(specs, *flattenRule(rule))
+	}
+	status, diags := policyStatusObject(ctx, policy.Status)
+	return flattenRule(policy.Spec), specs, status, diags
+}
+
+var initFQDNRegexCacheOnce sync.Once
+
+// expandPolicyRules converts the spec and specs attributes of a policy and
+// validates the resulting rules the same way the Cilium agent does.
+func expandPolicyRules(spec *ruleModel, specs []ruleModel) (*api.Rule, api.Rules, error) {
+	// Sanitize compiles FQDN patterns through a cache which the Cilium agent
+	// sets up when it starts.
+	initFQDNRegexCacheOnce.Do(func() {
+		_ = re.InitRegexCompileLRU(defaults.FQDNRegexCompileLRUSize)
+	})
+
+	var rule *api.Rule
+	if spec != nil {
+		var err error
+		if rule, err = expandRule(spec); err != nil {
+			return nil, nil, fmt.Errorf("spec: %w", err)
+		}
+		if err := rule.DeepCopy().Sanitize(); err != nil {
+			return nil, nil, fmt.Errorf("spec: %w", err)
+		}
+	}
+
+	var rules api.Rules
+	for i := range specs {
+		r, err := expandRule(&specs[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("specs[%d]: %w", i, err)
+		}
+		if err := r.DeepCopy().Sanitize(); err != nil {
+			return nil, nil, fmt.Errorf("specs[%d]: %w", i, err)
+		}
+		rules = append(rules, r)
+	}
+
+	return rule, rules, nil
+}
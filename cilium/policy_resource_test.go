@@ -0,0 +1,59 @@
+package cilium
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+)
+
+// TestClusterwidePolicyClient runs the shared policy resource logic against
+// CiliumClusterwideNetworkPolicies, which it handles as
+// CiliumNetworkPolicies.
+func TestClusterwidePolicyClient(t *testing.T) {
+	ctx := context.Background()
+	client := &CiliumClient{CiliumClientset: ciliumfake.NewSimpleClientset()}
+	policies := clusterwideNetworkPolicyClient(client)
+
+	rule := func(app string) *ruleModel {
+		return &ruleModel{
+			EndpointSelector: &selectorModel{MatchLabels: map[string]string{"app": app}},
+			Description:      types.StringNull(),
+		}
+	}
+
+	var diags diag.Diagnostics
+	meta := metav1.ObjectMeta{Name: "web", Labels: map[string]string{"team": "web"}}
+	if created := policies.createPolicy(ctx, meta, rule("web"), nil, &diags); created == nil || created.Namespace != "" {
+		t.Fatalf("unexpected policy %+v: %v", created, diags)
+	}
+
+	meta.Labels = map[string]string{"team": "platform"}
+	if updated := policies.updatePolicy(ctx, meta, rule("api"), nil, &diags); updated == nil {
+		t.Fatal(diags)
+	}
+	ccnp, err := client.GetCiliumClusterwideNetworkPolicy(ctx, "web")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ccnp.Labels["team"] != "platform" || flattenRule(ccnp.Spec).EndpointSelector.MatchLabels["app"] != "api" {
+		t.Errorf("policy not updated: %+v", ccnp)
+	}
+
+	if read := policies.readPolicy(ctx, "", "web", false, &diags); read == nil || read.Name != "web" {
+		t.Errorf("unexpected policy %+v: %v", read, diags)
+	}
+	policies.deletePolicy(ctx, "", "web", &diags)
+	if read := policies.readPolicy(ctx, "", "web", false, &diags); read != nil {
+		t.Errorf("expected a deleted policy to be missing, got %+v", read)
+	}
+	// Deleting a policy which is already gone is not an error.
+	policies.deletePolicy(ctx, "", "web", &diags)
+	if diags.HasError() {
+		t.Error(diags)
+	}
+}
@@ -0,0 +1,1309 @@
+package cilium
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	slimv1 "github.com/cilium/cilium/pkg/k8s/slim/k8s/apis/meta/v1"
+	"github.com/cilium/cilium/pkg/labels"
+	"github.com/cilium/cilium/pkg/policy/api"
+	"github.com/cilium/cilium/pkg/policy/api/kafka"
+)
+
+// ruleModel maps an api.Rule, the spec of CiliumNetworkPolicy and
+// CiliumClusterwideNetworkPolicy objects.
+type ruleModel struct {
+	EndpointSelector *selectorModel         `tfsdk:"endpoint_selector"`
+	NodeSelector     *selectorModel         `tfsdk:"node_selector"`
+	Ingress          []ingressRuleModel     `tfsdk:"ingress"`
+	IngressDeny      []ingressDenyRuleModel `tfsdk:"ingress_deny"`
+	Egress           []egressRuleModel      `tfsdk:"egress"`
+	EgressDeny       []egressDenyRuleModel  `tfsdk:"egress_deny"`
+	Labels           []labelModel           `tfsdk:"labels"`
+	Description      types.String           `tfsdk:"description"`
+}
+
+type selectorModel struct {
+	MatchLabels      map[string]string          `tfsdk:"match_labels"`
+	MatchExpressions []selectorRequirementModel `tfsdk:"match_expressions"`
+}
+
+type selectorRequirementModel struct {
+	Key      types.String `tfsdk:"key"`
+	Operator types.String `tfsdk:"operator"`
+	Values   []string     `tfsdk:"values"`
+}
+
+type ingressRuleModel struct {
+	FromEndpoints []selectorModel `tfsdk:"from_endpoints"`
+	FromRequires  []selectorModel `tfsdk:"from_requires"`
+	FromCIDR      []string        `tfsdk:"from_cidr"`
+	FromCIDRSet   []cidrRuleModel `tfsdk:"from_cidr_set"`
+	FromEntities  []string        `tfsdk:"from_entities"`
+	ToPorts       []portRuleModel `tfsdk:"to_ports"`
+	ICMPs         []icmpRuleModel `tfsdk:"icmps"`
+}
+
+type ingressDenyRuleModel struct {
+	FromEndpoints []selectorModel     `tfsdk:"from_endpoints"`
+	FromRequires  []selectorModel     `tfsdk:"from_requires"`
+	FromCIDR      []string            `tfsdk:"from_cidr"`
+	FromCIDRSet   []cidrRuleModel     `tfsdk:"from_cidr_set"`
+	FromEntities  []string            `tfsdk:"from_entities"`
+	ToPorts       []portDenyRuleModel `tfsdk:"to_ports"`
+	ICMPs         []icmpRuleModel     `tfsdk:"icmps"`
+}
+
+type egressRuleModel struct {
+	ToEndpoints []selectorModel     `tfsdk:"to_endpoints"`
+	ToRequires  []selectorModel     `tfsdk:"to_requires"`
+	ToCIDR      []string            `tfsdk:"to_cidr"`
+	ToCIDRSet   []cidrRuleModel     `tfsdk:"to_cidr_set"`
+	ToEntities  []string            `tfsdk:"to_entities"`
+	ToServices  []serviceModel      `tfsdk:"to_services"`
+	ToFQDNs     []fqdnSelectorModel `tfsdk:"to_fqdns"`
+	ToPorts     []portRuleModel     `tfsdk:"to_ports"`
+	ICMPs       []icmpRuleModel     `tfsdk:"icmps"`
+}
+
+type egressDenyRuleModel struct {
+	ToEndpoints []selectorModel     `tfsdk:"to_endpoints"`
+	ToRequires  []selectorModel     `tfsdk:"to_requires"`
+	ToCIDR      []string            `tfsdk:"to_cidr"`
+	ToCIDRSet   []cidrRuleModel     `tfsdk:"to_cidr_set"`
+	ToEntities  []string            `tfsdk:"to_entities"`
+	ToServices  []serviceModel      `tfsdk:"to_services"`
+	ToPorts     []portDenyRuleModel `tfsdk:"to_ports"`
+	ICMPs       []icmpRuleModel     `tfsdk:"icmps"`
+}
+
+type cidrRuleModel struct {
+	CIDR   types.String `tfsdk:"cidr"`
+	Except []string     `tfsdk:"except"`
+}
+
+type serviceModel struct {
+	K8sService         *k8sServiceModel         `tfsdk:"k8s_service"`
+	K8sServiceSelector *k8sServiceSelectorModel `tfsdk:"k8s_service_selector"`
+}
+
+type k8sServiceModel struct {
+	ServiceName types.String `tfsdk:"service_name"`
+	Namespace   types.String `tfsdk:"namespace"`
+}
+
+type k8sServiceSelectorModel struct {
+	Selector  *selectorModel `tfsdk:"selector"`
+	Namespace types.String   `tfsdk:"namespace"`
+}
+
+type fqdnSelectorModel struct {
+	MatchName    types.String `tfsdk:"match_name"`
+	MatchPattern types.String `tfsdk:"match_pattern"`
+}
+
+type portRuleModel struct {
+	Ports          []portProtocolModel `tfsdk:"ports"`
+	TerminatingTLS *tlsContextModel    `tfsdk:"terminating_tls"`
+	OriginatingTLS *tlsContextModel    `tfsdk:"originating_tls"`
+	ServerNames    []string            `tfsdk:"server_names"`
+	Rules          *l7RulesModel       `tfsdk:"rules"`
+}
+
+type portDenyRuleModel struct {
+	Ports []portProtocolModel `tfsdk:"ports"`
+}
+
+type portProtocolModel struct {
+	Port     types.String `tfsdk:"port"`
+	Protocol types.String `tfsdk:"protocol"`
+}
+
+type tlsContextModel struct {
+	Secret      *secretModel `tfsdk:"secret"`
+	TrustedCA   types.String `tfsdk:"trusted_ca"`
+	Certificate types.String `tfsdk:"certificate"`
+	PrivateKey  types.String `tfsdk:"private_key"`
+}
+
+type secretModel struct {
+	Namespace types.String `tfsdk:"namespace"`
+	Name      types.String `tfsdk:"name"`
+}
+
+type l7RulesModel struct {
+	HTTP    []httpRuleModel     `tfsdk:"http"`
+	Kafka   []kafkaRuleModel    `tfsdk:"kafka"`
+	DNS     []fqdnSelectorModel `tfsdk:"dns"`
+	L7Proto types.String        `tfsdk:"l7proto"`
+	L7      []map[string]string `tfsdk:"l7"`
+}
+
+type httpRuleModel struct {
+	Path          types.String       `tfsdk:"path"`
+	Method        types.String       `tfsdk:"method"`
+	Host          types.String       `tfsdk:"host"`
+	Headers       []string           `tfsdk:"headers"`
+	HeaderMatches []headerMatchModel `tfsdk:"header_matches"`
+}
+
+type headerMatchModel struct {
+	Mismatch types.String `tfsdk:"mismatch"`
+	Name     types.String `tfsdk:"name"`
+	Secret   *secretModel `tfsdk:"secret"`
+	Value    types.String `tfsdk:"value"`
+}
+
+type kafkaRuleModel struct {
+	Role       types.String `tfsdk:"role"`
+	APIKey     types.String `tfsdk:"api_key"`
+	APIVersion types.String `tfsdk:"api_version"`
+	ClientID   types.String `tfsdk:"client_id"`
+	Topic      types.String `tfsdk:"topic"`
+}
+
+type icmpRuleModel struct {
+	Fields []icmpFieldModel `tfsdk:"fields"`
+}
+
+type icmpFieldModel struct {
+	Family types.String `tfsdk:"family"`
+	Type   types.Int64  `tfsdk:"type"`
+}
+
+type labelModel struct {
+	Key    types.String `tfsdk:"key"`
+	Value  types.String `tfsdk:"value"`
+	Source types.String `tfsdk:"source"`
+}
+
+// ruleAttributes returns the schema of a policy rule. The attribute names
+// follow the snake_case form of the fields of api.Rule.
+func ruleAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"endpoint_selector": schema.SingleNestedAttribute{
+			Description: "Selects the endpoints the rule applies to. An empty object selects all endpoints.",
+			Optional:    true,
+			Attributes:  selectorAttributes(),
+		},
+		"node_selector": schema.SingleNestedAttribute{
+			Description: "Selects the nodes the rule applies to, making it a host policy. Only valid in clusterwide policies and mutually exclusive with endpoint_selector.",
+			Optional:    true,
+			Attributes:  selectorAttributes(),
+		},
+		"ingress": schema.ListNestedAttribute{
+			Description: "Ingress rules; traffic matching any of them is allowed.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: ingressAttributes(portRuleAttributes()),
+			},
+		},
+		"ingress_deny": schema.ListNestedAttribute{
+			Description: "Ingress deny rules; traffic matching any of them is denied, even if allowed by another rule.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: ingressAttributes(portDenyRuleAttributes()),
+			},
+		},
+		"egress": schema.ListNestedAttribute{
+			Description: "Egress rules; traffic matching any of them is allowed.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: egressAttributes(portRuleAttributes(), true),
+			},
+		},
+		"egress_deny": schema.ListNestedAttribute{
+			Description: "Egress deny rules; traffic matching any of them is denied, even if allowed by another rule.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: egressAttributes(portDenyRuleAttributes(), false),
+			},
+		},
+		"labels": schema.ListNestedAttribute{
+			Description: "Labels identifying the rule.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						Required: true,
+					},
+					"value": schema.StringAttribute{
+						Optional: true,
+					},
+					"source": schema.StringAttribute{
+						Optional: true,
+					},
+				},
+			},
+		},
+		"description": schema.StringAttribute{
+			Description: "Free form description of the rule.",
+			Optional:    true,
+		},
+	}
+}
+
+func selectorAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"match_labels": schema.MapAttribute{
+			Description: "Labels the endpoint must carry. Keys may be prefixed with a label source, e.g. k8s:io.kubernetes.pod.namespace.",
+			ElementType: types.StringType,
+			Optional:    true,
+		},
+		"match_expressions": schema.ListNestedAttribute{
+			Description: "Label selector requirements, all of which must match.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						Required: true,
+					},
+					"operator": schema.StringAttribute{
+						Description: "One of In, NotIn, Exists or DoesNotExist.",
+						Required:    true,
+					},
+					"values": schema.ListAttribute{
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
+			},
+		},
+	}
+}
+
+func selectorListAttribute(description string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: description,
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: selectorAttributes(),
+		},
+	}
+}
+
+func cidrAttributes() (schema.ListAttribute, schema.ListNestedAttribute) {
+	return schema.ListAttribute{
+		Description: "CIDRs of the peers.",
+		ElementType: types.StringType,
+		Optional:    true,
+	}, schema.ListNestedAttribute{
+		Description: "CIDRs of the peers, with optional exceptions.",
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"cidr": schema.StringAttribute{
+					Required: true,
+				},
+				"except": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+func entitiesAttribute() schema.ListAttribute {
+	return schema.ListAttribute{
+		Description: "Entities of the peers, e.g. world, cluster, host, remote-node, kube-apiserver or all.",
+		ElementType: types.StringType,
+		Optional:    true,
+	}
+}
+
+func icmpsAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "ICMP rules.",
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"fields": schema.ListNestedAttribute{
+					Optional: true,
+					NestedObject: schema.NestedAttributeObject{
+						Attributes: map[string]schema.Attribute{
+							"family": schema.StringAttribute{
+								Description: "IPv4 or IPv6. Defaults to IPv4, like the CRD does.",
+								Optional:    true,
+								Computed:    true,
+								PlanModifiers: []planmodifier.String{
+									defaultStringValue(api.IPv4Family),
+								},
+							},
+							"type": schema.Int64Attribute{
+								Description: "ICMP message type.",
+								Required:    true,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func ingressAttributes(toPorts schema.ListNestedAttribute) map[string]schema.Attribute {
+	fromCIDR, fromCIDRSet := cidrAttributes()
+	return map[string]schema.Attribute{
+		"from_endpoints": selectorListAttribute("Endpoints allowed to send traffic."),
+		"from_requires":  selectorListAttribute("Additional constraints all peers must satisfy."),
+		"from_cidr":      fromCIDR,
+		"from_cidr_set":  fromCIDRSet,
+		"from_entities":  entitiesAttribute(),
+		"to_ports":       toPorts,
+		"icmps":          icmpsAttribute(),
+	}
+}
+
+func egressAttributes(toPorts schema.ListNestedAttribute, withFQDNs bool) map[string]schema.Attribute {
+	toCIDR, toCIDRSet := cidrAttributes()
+	attributes := map[string]schema.Attribute{
+		"to_endpoints": selectorListAttribute("Endpoints traffic may be sent to."),
+		"to_requires":  selectorListAttribute("Additional constraints all peers must satisfy."),
+		"to_cidr":      toCIDR,
+		"to_cidr_set":  toCIDRSet,
+		"to_entities":  entitiesAttribute(),
+		"to_services": schema.ListNestedAttribute{
+			Description: "Kubernetes services traffic may be sent to.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"k8s_service": schema.SingleNestedAttribute{
+						Optional: true,
+						Attributes: map[string]schema.Attribute{
+							"service_name": schema.StringAttribute{
+								Optional: true,
+							},
+							"namespace": schema.StringAttribute{
+								Optional: true,
+							},
+						},
+					},
+					"k8s_service_selector": schema.SingleNestedAttribute{
+						Optional: true,
+						Attributes: map[string]schema.Attribute{
+							"selector": schema.SingleNestedAttribute{
+								Required:   true,
+								Attributes: selectorAttributes(),
+							},
+							"namespace": schema.StringAttribute{
+								Optional: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		"to_ports": toPorts,
+		"icmps":    icmpsAttribute(),
+	}
+	if withFQDNs {
+		attributes["to_fqdns"] = schema.ListNestedAttribute{
+			Description: "DNS names traffic may be sent to. Requires a DNS rule allowing the lookups.",
+			Optional:    true,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: fqdnSelectorAttributes(),
+			},
+		}
+	}
+	return attributes
+}
+
+func fqdnSelectorAttributes() map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"match_name": schema.StringAttribute{
+			Optional: true,
+		},
+		"match_pattern": schema.StringAttribute{
+			Optional: true,
+		},
+	}
+}
+
+func portsAttribute() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "Layer 4 ports.",
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"port": schema.StringAttribute{
+					Description: "Port number or name.",
+					Required:    true,
+				},
+				"protocol": schema.StringAttribute{
+					Description: "TCP, UDP, SCTP or ANY.",
+					Optional:    true,
+				},
+			},
+		},
+	}
+}
+
+func secretAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"namespace": schema.StringAttribute{
+				Optional: true,
+			},
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+		},
+	}
+}
+
+func tlsContextAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Attributes: map[string]schema.Attribute{
+			"secret":      secretAttribute(),
+			"trusted_ca":  schema.StringAttribute{Optional: true},
+			"certificate": schema.StringAttribute{Optional: true},
+			"private_key": schema.StringAttribute{Optional: true},
+		},
+	}
+}
+
+func portRuleAttributes() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "Ports, and optionally layer 7 rules, traffic is allowed on.",
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"ports":           portsAttribute(),
+				"terminating_tls": tlsContextAttribute(),
+				"originating_tls": tlsContextAttribute(),
+				"server_names": schema.ListAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+				},
+				"rules": schema.SingleNestedAttribute{
+					Description: "Layer 7 rules.",
+					Optional:    true,
+					Attributes: map[string]schema.Attribute{
+						"http": schema.ListNestedAttribute{
+							Optional: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"path":   schema.StringAttribute{Optional: true},
+									"method": schema.StringAttribute{Optional: true},
+									"host":   schema.StringAttribute{Optional: true},
+									"headers": schema.ListAttribute{
+										ElementType: types.StringType,
+										Optional:    true,
+									},
+									"header_matches": schema.ListNestedAttribute{
+										Optional: true,
+										NestedObject: schema.NestedAttributeObject{
+											Attributes: map[string]schema.Attribute{
+												"mismatch": schema.StringAttribute{Optional: true},
+												"name":     schema.StringAttribute{Required: true},
+												"secret":   secretAttribute(),
+												"value":    schema.StringAttribute{Optional: true},
+											},
+										},
+									},
+								},
+							},
+						},
+						"kafka": schema.ListNestedAttribute{
+							Optional: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"role":        schema.StringAttribute{Optional: true},
+									"api_key":     schema.StringAttribute{Optional: true},
+									"api_version": schema.StringAttribute{Optional: true},
+									"client_id":   schema.StringAttribute{Optional: true},
+									"topic":       schema.StringAttribute{Optional: true},
+								},
+							},
+						},
+						"dns": schema.ListNestedAttribute{
+							Optional: true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: fqdnSelectorAttributes(),
+							},
+						},
+						"l7proto": schema.StringAttribute{
+							Optional: true,
+						},
+						"l7": schema.ListAttribute{
+							ElementType: types.MapType{ElemType: types.StringType},
+							Optional:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func portDenyRuleAttributes() schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Description: "Ports traffic is denied on.",
+		Optional:    true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"ports": portsAttribute(),
+			},
+		},
+	}
+}
+
+// validatePolicyRules checks the spec and specs attributes of a policy
+// configuration: at least one rule must be set and every rule must select
+// either endpoints or, for clusterwide policies only, nodes.
+func validatePolicyRules(ctx context.Context, config tfsdk.Config, clusterwide bool) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var spec types.Object
+	var specs types.List
+	diags.Append(config.GetAttribute(ctx, path.Root("spec"), &spec)...)
+	diags.Append(config.GetAttribute(ctx, path.Root("specs"), &specs)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	if spec.IsNull() && specs.IsNull() {
+		diags.AddAttributeError(
+			path.Root("spec"),
+			"Missing Policy Rule",
+			"One of spec or specs must be set.",
+		)
+		return diags
+	}
+
+	if !spec.IsNull() && !spec.IsUnknown() {
+		validateRuleSelectors(spec, path.Root("spec"), clusterwide, &diags)
+	}
+	if !specs.IsNull() && !specs.IsUnknown() {
+		for i, elem := range specs.Elements() {
+			if obj, ok := elem.(types.Object); ok && !obj.IsNull() && !obj.IsUnknown() {
+				validateRuleSelectors(obj, path.Root("specs").AtListIndex(i), clusterwide, &diags)
+			}
+		}
+	}
+
+	return diags
+}
+
+// validateRuleSelectors reports a rule which the API server would reject
+// because of its endpoint_selector and node_selector attributes.
+func validateRuleSelectors(rule types.Object, p path.Path, clusterwide bool, diags *diag.Diagnostics) {
+	attributes := rule.Attributes()
+	endpointSelector, nodeSelector := attributes["endpoint_selector"], attributes["node_selector"]
+	if endpointSelector == nil || nodeSelector == nil || endpointSelector.IsUnknown() || nodeSelector.IsUnknown() {
+		return
+	}
+
+	switch {
+	case !clusterwide && !nodeSelector.IsNull():
+		diags.AddAttributeError(
+			p.AtName("node_selector"),
+			"Invalid Node Selector",
+			"node_selector is only supported by cilium_clusterwide_network_policy. Use endpoint_selector to select the endpoints of the namespace.",
+		)
+	case !clusterwide && endpointSelector.IsNull():
+		diags.AddAttributeError(
+			p.AtName("endpoint_selector"),
+			"Missing Endpoint Selector",
+			"Every rule of a CiliumNetworkPolicy must set endpoint_selector. Use an empty object to select all endpoints of the namespace.",
+		)
+	case endpointSelector.IsNull() && nodeSelector.IsNull():
+		diags.AddAttributeError(
+			p,
+			"Missing Selector",
+			"Every rule must set one of endpoint_selector or node_selector.",
+		)
+	case !endpointSelector.IsNull() && !nodeSelector.IsNull():
+		diags.AddAttributeError(
+			p.AtName("node_selector"),
+			"Conflicting Selectors",
+			"A rule cannot set both endpoint_selector and node_selector.",
+		)
+	}
+}
+
+// expandRule converts the Terraform representation of a rule into an
+// api.Rule.
+func expandRule(m *ruleModel) (*api.Rule, error) {
+	rule := &api.Rule{
+		Description: m.Description.ValueString(),
+	}
+
+	// An empty endpoint selector selects all endpoints, but api.Rule only
+	// serializes a selector which is set.
+	if m.EndpointSelector != nil {
+		rule.EndpointSelector = expandSelector(m.EndpointSelector)
+	}
+	if m.NodeSelector != nil {
+		rule.NodeSelector = expandSelector(m.NodeSelector)
+	}
+
+	for _, l := range m.Labels {
+		rule.Labels = append(rule.Labels, labels.NewLabel(l.Key.ValueString(), l.Value.ValueString(), l.Source.ValueString()))
+	}
+
+	for _, in := range m.Ingress {
+		icmps, err := expandICMPRules(in.ICMPs)
+		if err != nil {
+			return nil, err
+		}
+		rule.Ingress = append(rule.Ingress, api.IngressRule{
+			IngressCommonRule: api.IngressCommonRule{
+				FromEndpoints: expandSelectors(in.FromEndpoints),
+				FromRequires:  expandSelectors(in.FromRequires),
+				FromCIDR:      expandCIDRs(in.FromCIDR),
+				FromCIDRSet:   expandCIDRRules(in.FromCIDRSet),
+				FromEntities:  expandEntities(in.FromEntities),
+			},
+			ToPorts: expandPortRules(in.ToPorts),
+			ICMPs:   icmps,
+		})
+	}
+
+	for _, in := range m.IngressDeny {
+		icmps, err := expandICMPRules(in.ICMPs)
+		if err != nil {
+			return nil, err
+		}
+		rule.IngressDeny = append(rule.IngressDeny, api.IngressDenyRule{
+			IngressCommonRule: api.IngressCommonRule{
+				FromEndpoints: expandSelectors(in.FromEndpoints),
+				FromRequires:  expandSelectors(in.FromRequires),
+				FromCIDR:      expandCIDRs(in.FromCIDR),
+				FromCIDRSet:   expandCIDRRules(in.FromCIDRSet),
+				FromEntities:  expandEntities(in.FromEntities),
+			},
+			ToPorts: expandPortDenyRules(in.ToPorts),
+			ICMPs:   icmps,
+		})
+	}
+
+	for _, eg := range m.Egress {
+		icmps, err := expandICMPRules(eg.ICMPs)
+		if err != nil {
+			return nil, err
+		}
+		egress := api.EgressRule{
+			EgressCommonRule: api.EgressCommonRule{
+				ToEndpoints: expandSelectors(eg.ToEndpoints),
+				ToRequires:  expandSelectors(eg.ToRequires),
+				ToCIDR:      expandCIDRs(eg.ToCIDR),
+				ToCIDRSet:   expandCIDRRules(eg.ToCIDRSet),
+				ToEntities:  expandEntities(eg.ToEntities),
+				ToServices:  expandServices(eg.ToServices),
+			},
+			ToPorts: expandPortRules(eg.ToPorts),
+			ICMPs:   icmps,
+		}
+		for _, fqdn := range eg.ToFQDNs {
+			egress.ToFQDNs = append(egress.ToFQDNs, api.FQDNSelector{
+				MatchName:    fqdn.MatchName.ValueString(),
+				MatchPattern: fqdn.MatchPattern.ValueString(),
+			})
+		}
+		rule.Egress = append(rule.Egress, egress)
+	}
+
+	for _, eg := range m.EgressDeny {
+		icmps, err := expandICMPRules(eg.ICMPs)
+		if err != nil {
+			return nil, err
+		}
+		rule.EgressDeny = append(rule.EgressDeny, api.EgressDenyRule{
+			EgressCommonRule: api.EgressCommonRule{
+				ToEndpoints: expandSelectors(eg.ToEndpoints),
+				ToRequires:  expandSelectors(eg.ToRequires),
+				ToCIDR:      expandCIDRs(eg.ToCIDR),
+				ToCIDRSet:   expandCIDRRules(eg.ToCIDRSet),
+				ToEntities:  expandEntities(eg.ToEntities),
+				ToServices:  expandServices(eg.ToServices),
+			},
+			ToPorts: expandPortDenyRules(eg.ToPorts),
+			ICMPs:   icmps,
+		})
+	}
+
+	return rule, nil
+}
+
+// expandSelector converts a selector into an api.EndpointSelector. Label
+// keys are stored in their extended form, e.g. any.app, which is what
+// api.EndpointSelector holds after decoding an object from the API server.
+func expandSelector(m *selectorModel) api.EndpointSelector {
+	var matchLabels map[string]string
+	if m.MatchLabels != nil {
+		matchLabels = make(map[string]string, len(m.MatchLabels))
+		for k, v := range m.MatchLabels {
+			matchLabels[labels.GetExtendedKeyFrom(k)] = v
+		}
+	}
+
+	var requirements []slimv1.LabelSelectorRequirement
+	for _, expr := range m.MatchExpressions {
+		requirements = append(requirements, slimv1.LabelSelectorRequirement{
+			Key:      labels.GetExtendedKeyFrom(expr.Key.ValueString()),
+			Operator: slimv1.LabelSelectorOperator(expr.Operator.ValueString()),
+			Values:   expr.Values,
+		})
+	}
+
+	return api.NewESFromMatchRequirements(matchLabels, requirements)
+}
+
+// expandServiceSelector converts a selector of Kubernetes services. Unlike
+// endpoint selectors, service selectors are serialized with their keys
+// unchanged.
+func expandServiceSelector(m *selectorModel) api.ServiceSelector {
+	var requirements []slimv1.LabelSelectorRequirement
+	for _, expr := range m.MatchExpressions {
+		requirements = append(requirements, slimv1.LabelSelectorRequirement{
+			Key:      expr.Key.ValueString(),
+			Operator: slimv1.LabelSelectorOperator(expr.Operator.ValueString()),
+			Values:   expr.Values,
+		})
+	}
+	return api.ServiceSelector(api.NewESFromMatchRequirements(m.MatchLabels, requirements))
+}
+
+func expandSelectors(models []selectorModel) []api.EndpointSelector {
+	var selectors []api.EndpointSelector
+	for i := range models {
+		selectors = append(selectors, expandSelector(&models[i]))
+	}
+	return selectors
+}
+
+func expandCIDRs(cidrs []string) api.CIDRSlice {
+	var slice api.CIDRSlice
+	for _, cidr := range cidrs {
+		slice = append(slice, api.CIDR(cidr))
+	}
+	return slice
+}
+
+func expandCIDRRules(models []cidrRuleModel) api.CIDRRuleSlice {
+	var rules api.CIDRRuleSlice
+	for _, m := range models {
+		rules = append(rules, api.CIDRRule{
+			Cidr:        api.CIDR(m.CIDR.ValueString()),
+			ExceptCIDRs: expandCIDRs(m.Except),
+		})
+	}
+	return rules
+}
+
+func expandEntities(entities []string) api.EntitySlice {
+	var slice api.EntitySlice
+	for _, entity := range entities {
+		slice = append(slice, api.Entity(entity))
+	}
+	return slice
+}
+
+func expandServices(models []serviceModel) []api.Service {
+	var services []api.Service
+	for _, m := range models {
+		var service api.Service
+		if m.K8sService != nil {
+			service.K8sService = &api.K8sServiceNamespace{
+				ServiceName: m.K8sService.ServiceName.ValueString(),
+				Namespace:   m.K8sService.Namespace.ValueString(),
+			}
+		}
+		if m.K8sServiceSelector != nil {
+			selector := &api.K8sServiceSelectorNamespace{
+				Namespace: m.K8sServiceSelector.Namespace.ValueString(),
+			}
+			if m.K8sServiceSelector.Selector != nil {
+				selector.Selector = expandServiceSelector(m.K8sServiceSelector.Selector)
+			}
+			service.K8sServiceSelector = selector
+		}
+		services = append(services, service)
+	}
+	return services
+}
+
+func expandPorts(models []portProtocolModel) []api.PortProtocol {
+	var ports []api.PortProtocol
+	for _, m := range models {
+		ports = append(ports, api.PortProtocol{
+			Port:     m.Port.ValueString(),
+			Protocol: api.L4Proto(m.Protocol.ValueString()),
+		})
+	}
+	return ports
+}
+
+func expandSecret(m *secretModel) *api.Secret {
+	if m == nil {
+		return nil
+	}
+	return &api.Secret{
+		Namespace: m.Namespace.ValueString(),
+		Name:      m.Name.ValueString(),
+	}
+}
+
+func expandTLSContext(m *tlsContextModel) *api.TLSContext {
+	if m == nil {
+		return nil
+	}
+	return &api.TLSContext{
+		Secret:      expandSecret(m.Secret),
+		TrustedCA:   m.TrustedCA.ValueString(),
+		Certificate: m.Certificate.ValueString(),
+		PrivateKey:  m.PrivateKey.ValueString(),
+	}
+}
+
+func expandPortRules(models []portRuleModel) api.PortRules {
+	var rules api.PortRules
+	for _, m := range models {
+		rules = append(rules, api.PortRule{
+			Ports:          expandPorts(m.Ports),
+			TerminatingTLS: expandTLSContext(m.TerminatingTLS),
+			OriginatingTLS: expandTLSContext(m.OriginatingTLS),
+			ServerNames:    m.ServerNames,
+			Rules:          expandL7Rules(m.Rules),
+		})
+	}
+	return rules
+}
+
+func expandPortDenyRules(models []portDenyRuleModel) api.PortDenyRules {
+	var rules api.PortDenyRules
+	for _, m := range models {
+		rules = append(rules, api.PortDenyRule{
+			Ports: expandPorts(m.Ports),
+		})
+	}
+	return rules
+}
+
+func expandL7Rules(m *l7RulesModel) *api.L7Rules {
+	if m == nil {
+		return nil
+	}
+
+	rules := &api.L7Rules{
+		L7Proto: m.L7Proto.ValueString(),
+	}
+	for _, h := range m.HTTP {
+		http := api.PortRuleHTTP{
+			Path:    h.Path.ValueString(),
+			Method:  h.Method.ValueString(),
+			Host:    h.Host.ValueString(),
+			Headers: h.Headers,
+		}
+		for _, hm := range h.HeaderMatches {
+			http.HeaderMatches = append(http.HeaderMatches, &api.HeaderMatch{
+				Mismatch: api.MismatchAction(hm.Mismatch.ValueString()),
+				Name:     hm.Name.ValueString(),
+				Secret:   expandSecret(hm.Secret),
+				Value:    hm.Value.ValueString(),
+			})
+		}
+		rules.HTTP = append(rules.HTTP, http)
+	}
+	for _, k := range m.Kafka {
+		rules.Kafka = append(rules.Kafka, kafka.PortRule{
+			Role:       k.Role.ValueString(),
+			APIKey:     k.APIKey.ValueString(),
+			APIVersion: k.APIVersion.ValueString(),
+			ClientID:   k.ClientID.ValueString(),
+			Topic:      k.Topic.ValueString(),
+		})
+	}
+	for _, d := range m.DNS {
+		rules.DNS = append(rules.DNS, api.PortRuleDNS{
+			MatchName:    d.MatchName.ValueString(),
+			MatchPattern: d.MatchPattern.ValueString(),
+		})
+	}
+	for _, l7 := range m.L7 {
+		rules.L7 = append(rules.L7, api.PortRuleL7(l7))
+	}
+	return rules
+}
+
+func expandICMPRules(models []icmpRuleModel) (api.ICMPRules, error) {
+	var rules api.ICMPRules
+	for _, m := range models {
+		var rule api.ICMPRule
+		for _, f := range m.Fields {
+			icmpType := f.Type.ValueInt64()
+			if icmpType < 0 || icmpType > math.MaxUint8 {
+				return nil, fmt.Errorf("ICMP type %d is out of range, it must be between 0 and %d", icmpType, math.MaxUint8)
+			}
+			rule.Fields = append(rule.Fields, api.ICMPField{
+				Family: f.Family.ValueString(),
+				Type:   uint8(icmpType),
+			})
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// flattenRule converts an api.Rule into its Terraform representation. Empty
+// values are mapped to null so that omitted attributes do not show a diff.
+func flattenRule(rule *api.Rule) *ruleModel {
+	if rule == nil {
+		return nil
+	}
+
+	m := &ruleModel{
+		Description: stringValueOrNull(rule.Description),
+	}
+
+	if rule.EndpointSelector.LabelSelector != nil {
+		m.EndpointSelector = flattenSelector(rule.EndpointSelector)
+	}
+	if rule.NodeSelector.LabelSelector != nil {
+		m.NodeSelector = flattenSelector(rule.NodeSelector)
+	}
+
+	for _, l := range rule.Labels {
+		m.Labels = append(m.Labels, labelModel{
+			Key:    types.StringValue(l.Key),
+			Value:  stringValueOrNull(l.Value),
+			Source: stringValueOrNull(l.Source),
+		})
+	}
+
+	for _, in := range rule.Ingress {
+		m.Ingress = append(m.Ingress, ingressRuleModel{
+			FromEndpoints: flattenSelectors(in.FromEndpoints),
+			FromRequires:  flattenSelectors(in.FromRequires),
+			FromCIDR:      flattenCIDRs(in.FromCIDR),
+			FromCIDRSet:   flattenCIDRRules(in.FromCIDRSet),
+			FromEntities:  flattenEntities(in.FromEntities),
+			ToPorts:       flattenPortRules(in.ToPorts),
+			ICMPs:         flattenICMPRules(in.ICMPs),
+		})
+	}
+
+	for _, in := range rule.IngressDeny {
+		m.IngressDeny = append(m.IngressDeny, ingressDenyRuleModel{
+			FromEndpoints: flattenSelectors(in.FromEndpoints),
+			FromRequires:  flattenSelectors(in.FromRequires),
+			FromCIDR:      flattenCIDRs(in.FromCIDR),
+			FromCIDRSet:   flattenCIDRRules(in.FromCIDRSet),
+			FromEntities:  flattenEntities(in.FromEntities),
+			ToPorts:       flattenPortDenyRules(in.ToPorts),
+			ICMPs:         flattenICMPRules(in.ICMPs),
+		})
+	}
+
+	for _, eg := range rule.Egress {
+		egress := egressRuleModel{
+			ToEndpoints: flattenSelectors(eg.ToEndpoints),
+			ToRequires:  flattenSelectors(eg.ToRequires),
+			ToCIDR:      flattenCIDRs(eg.ToCIDR),
+			ToCIDRSet:   flattenCIDRRules(eg.ToCIDRSet),
+			ToEntities:  flattenEntities(eg.ToEntities),
+			ToServices:  flattenServices(eg.ToServices),
+			ToPorts:     flattenPortRules(eg.ToPorts),
+			ICMPs:       flattenICMPRules(eg.ICMPs),
+		}
+		for _, fqdn := range eg.ToFQDNs {
+			egress.ToFQDNs = append(egress.ToFQDNs, fqdnSelectorModel{
+				MatchName:    stringValueOrNull(fqdn.MatchName),
+				MatchPattern: stringValueOrNull(fqdn.MatchPattern),
+			})
+		}
+		m.Egress = append(m.Egress, egress)
+	}
+
+	for _, eg := range rule.EgressDeny {
+		m.EgressDeny = append(m.EgressDeny, egressDenyRuleModel{
+			ToEndpoints: flattenSelectors(eg.ToEndpoints),
+			ToRequires:  flattenSelectors(eg.ToRequires),
+			ToCIDR:      flattenCIDRs(eg.ToCIDR),
+			ToCIDRSet:   flattenCIDRRules(eg.ToCIDRSet),
+			ToEntities:  flattenEntities(eg.ToEntities),
+			ToServices:  flattenServices(eg.ToServices),
+			ToPorts:     flattenPortDenyRules(eg.ToPorts),
+			ICMPs:       flattenICMPRules(eg.ICMPs),
+		})
+	}
+
+	return m
+}
+
+// flattenSelector reverses expandSelector, turning extended label keys such
+// as any.app back into the form used in policy manifests.
+func flattenSelector(selector api.EndpointSelector) *selectorModel {
+	m := &selectorModel{}
+	if selector.LabelSelector == nil {
+		return m
+	}
+
+	if len(selector.MatchLabels) > 0 {
+		m.MatchLabels = make(map[string]string, len(selector.MatchLabels))
+		for k, v := range selector.MatchLabels {
+			m.MatchLabels[ciliumKeyFrom(k)] = v
+		}
+	}
+
+	for _, expr := range selector.MatchExpressions {
+		m.MatchExpressions = append(m.MatchExpressions, selectorRequirementModel{
+			Key:      types.StringValue(ciliumKeyFrom(expr.Key)),
+			Operator: types.StringValue(string(expr.Operator)),
+			Values:   nilIfEmpty(expr.Values),
+		})
+	}
+
+	return m
+}
+
+// ciliumKeyFrom converts an extended label key back into the form used in
+// policy manifests. Keys of the any source are returned without source
+// prefix, as written by practitioners.
+func ciliumKeyFrom(extKey string) string {
+	return strings.TrimPrefix(labels.GetCiliumKeyFrom(extKey), labels.LabelSourceAny+":")
+}
+
+// flattenServiceSelector reverses expandServiceSelector.
+func flattenServiceSelector(selector api.ServiceSelector) *selectorModel {
+	m := &selectorModel{}
+	if selector.LabelSelector == nil {
+		return m
+	}
+	if len(selector.MatchLabels) > 0 {
+		m.MatchLabels = selector.MatchLabels
+	}
+	for _, expr := range selector.MatchExpressions {
+		m.MatchExpressions = append(m.MatchExpressions, selectorRequirementModel{
+			Key:      types.StringValue(expr.Key),
+			Operator: types.StringValue(string(expr.Operator)),
+			Values:   nilIfEmpty(expr.Values),
+		})
+	}
+	return m
+}
+
+func flattenSelectors(selectors []api.EndpointSelector) []selectorModel {
+	var models []selectorModel
+	for _, selector := range selectors {
+		models = append(models, *flattenSelector(selector))
+	}
+	return models
+}
+
+func flattenCIDRs(cidrs api.CIDRSlice) []string {
+	var slice []string
+	for _, cidr := range cidrs {
+		slice = append(slice, string(cidr))
+	}
+	return slice
+}
+
+func flattenCIDRRules(rules api.CIDRRuleSlice) []cidrRuleModel {
+	var models []cidrRuleModel
+	for _, rule := range rules {
+		// Generated rules are derived by the agent and never persisted.
+		if rule.Generated {
+			continue
+		}
+		models = append(models, cidrRuleModel{
+			CIDR:   types.StringValue(string(rule.Cidr)),
+			Except: flattenCIDRs(rule.ExceptCIDRs),
+		})
+	}
+	return models
+}
+
+func flattenEntities(entities api.EntitySlice) []string {
+	var slice []string
+	for _, entity := range entities {
+		slice = append(slice, string(entity))
+	}
+	return slice
+}
+
+func flattenServices(services []api.Service) []serviceModel {
+	var models []serviceModel
+	for _, service := range services {
+		var m serviceModel
+		if service.K8sService != nil {
+			m.K8sService = &k8sServiceModel{
+				ServiceName: stringValueOrNull(service.K8sService.ServiceName),
+				Namespace:   stringValueOrNull(service.K8sService.Namespace),
+			}
+		}
+		if service.K8sServiceSelector != nil {
+			m.K8sServiceSelector = &k8sServiceSelectorModel{
+				Selector:  flattenServiceSelector(service.K8sServiceSelector.Selector),
+				Namespace: stringValueOrNull(service.K8sServiceSelector.Namespace),
+			}
+		}
+		models = append(models, m)
+	}
+	return models
+}
+
+func flattenPorts(ports []api.PortProtocol) []portProtocolModel {
+	var models []portProtocolModel
+	for _, port := range ports {
+		models = append(models, portProtocolModel{
+			Port:     types.StringValue(port.Port),
+			Protocol: stringValueOrNull(string(port.Protocol)),
+		})
+	}
+	return models
+}
+
+func flattenSecret(secret *api.Secret) *secretModel {
+	if secret == nil {
+		return nil
+	}
+	return &secretModel{
+		Namespace: stringValueOrNull(secret.Namespace),
+		Name:      types.StringValue(secret.Name),
+	}
+}
+
+func flattenTLSContext(tls *api.TLSContext) *tlsContextModel {
+	if tls == nil {
+		return nil
+	}
+	return &tlsContextModel{
+		Secret:      flattenSecret(tls.Secret),
+		TrustedCA:   stringValueOrNull(tls.TrustedCA),
+		Certificate: stringValueOrNull(tls.Certificate),
+		PrivateKey:  stringValueOrNull(tls.PrivateKey),
+	}
+}
+
+func flattenPortRules(rules api.PortRules) []portRuleModel {
+	var models []portRuleModel
+	for _, rule := range rules {
+		models = append(models, portRuleModel{
+			Ports:          flattenPorts(rule.Ports),
+			TerminatingTLS: flattenTLSContext(rule.TerminatingTLS),
+			OriginatingTLS: flattenTLSContext(rule.OriginatingTLS),
+			ServerNames:    nilIfEmpty(rule.ServerNames),
+			Rules:          flattenL7Rules(rule.Rules),
+		})
+	}
+	return models
+}
+
+func flattenPortDenyRules(rules api.PortDenyRules) []portDenyRuleModel {
+	var models []portDenyRuleModel
+	for _, rule := range rules {
+		models = append(models, portDenyRuleModel{
+			Ports: flattenPorts(rule.Ports),
+		})
+	}
+	return models
+}
+
+func flattenL7Rules(rules *api.L7Rules) *l7RulesModel {
+	if rules == nil {
+		return nil
+	}
+
+	m := &l7RulesModel{
+		L7Proto: stringValueOrNull(rules.L7Proto),
+	}
+	for _, h := range rules.HTTP {
+		http := httpRuleModel{
+			Path:    stringValueOrNull(h.Path),
+			Method:  stringValueOrNull(h.Method),
+			Host:    stringValueOrNull(h.Host),
+			Headers: nilIfEmpty(h.Headers),
+		}
+		for _, hm := range h.HeaderMatches {
+			http.HeaderMatches = append(http.HeaderMatches, headerMatchModel{
+				Mismatch: stringValueOrNull(string(hm.Mismatch)),
+				Name:     types.StringValue(hm.Name),
+				Secret:   flattenSecret(hm.Secret),
+				Value:    stringValueOrNull(hm.Value),
+			})
+		}
+		m.HTTP = append(m.HTTP, http)
+	}
+	for _, k := range rules.Kafka {
+		m.Kafka = append(m.Kafka, kafkaRuleModel{
+			Role:       stringValueOrNull(k.Role),
+			APIKey:     stringValueOrNull(k.APIKey),
+			APIVersion: stringValueOrNull(k.APIVersion),
+			ClientID:   stringValueOrNull(k.ClientID),
+			Topic:      stringValueOrNull(k.Topic),
+		})
+	}
+	for _, d := range rules.DNS {
+		m.DNS = append(m.DNS, fqdnSelectorModel{
+			MatchName:    stringValueOrNull(d.MatchName),
+			MatchPattern: stringValueOrNull(d.MatchPattern),
+		})
+	}
+	for _, l7 := range rules.L7 {
+		m.L7 = append(m.L7, map[string]string(l7))
+	}
+	return m
+}
+
+func flattenICMPRules(rules api.ICMPRules) []icmpRuleModel {
+	var models []icmpRuleModel
+	for _, rule := range rules {
+		var m icmpRuleModel
+		for _, f := range rule.Fields {
+			m.Fields = append(m.Fields, icmpFieldModel{
+				Family: stringValueOrNull(f.Family),
+				Type:   types.Int64Value(int64(f.Type)),
+			})
+		}
+		models = append(models, m)
+	}
+	return models
+}
+
+// checkRulesImportable reports the rules of a policy which contain fields
+// that are lost when converted to the Terraform schema, and would thus be
+// removed the next time the policy is applied.
+func checkRulesImportable(spec *api.Rule, specs api.Rules) error {
+	var lossy []string
+	if spec != nil && !ruleRoundTrips(spec) {
+		lossy = append(lossy, "spec")
+	}
+	for i, rule := range specs {
+		if !ruleRoundTrips(rule) {
+			lossy = append(lossy, fmt.Sprintf("specs[%d]", i))
+		}
+	}
+	if len(lossy) > 0 {
+		return fmt.Errorf("fields not supported by the provider in %s", strings.Join(lossy, ", "))
+	}
+	return nil
+}
+
+func ruleRoundTrips(rule *api.Rule) bool {
+	expanded, err := expandRule(flattenRule(rule))
+	if err != nil {
+		return false
+	}
+	want, err := json.Marshal(rule)
+	if err != nil {
+		return false
+	}
+	got, err := json.Marshal(expanded)
+	return err == nil && bytes.Equal(got, want)
+}
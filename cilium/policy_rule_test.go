@@ -0,0 +1,243 @@
+package cilium
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"sigs.k8s.io/yaml"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/policy/api"
+)
+
+// TestRuleRoundTrip checks that flattening the rules of the example policies
+// and expanding them again yields the same rules.
+func TestRuleRoundTrip(t *testing.T) {
+	for _, file := range []string{
+		"../examples/resources/cnp_fqdn.yaml",
+		"../examples/resources/cnp_log4shell.yaml",
+		"../examples/resources/ccnp_test.yaml",
+		"../hack/cnp.yaml",
+	} {
+		t.Run(file, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var cnp ciliumv2.CiliumNetworkPolicy
+			if err := yaml.Unmarshal(data, &cnp); err != nil {
+				t.Fatal(err)
+			}
+
+			rules := cnp.Specs
+			if cnp.Spec != nil {
+				rules = append(api.Rules{cnp.Spec}, rules...)
+			}
+			if len(rules) == 0 {
+				t.Fatal("no rules in policy")
+			}
+
+			for _, rule := range rules {
+				expanded, err := expandRule(flattenRule(rule))
+				if err != nil {
+					t.Fatal(err)
+				}
+				want, _ := json.Marshal(rule)
+				got, _ := json.Marshal(expanded)
+				if string(got) != string(want) {
+					t.Errorf("round trip mismatch:\n got: %s\nwant: %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectorKeysRoundTrip(t *testing.T) {
+	m := &selectorModel{
+		MatchLabels: map[string]string{
+			"app":                             "web",
+			"k8s:io.kubernetes.pod.namespace": "kube-system",
+			"reserved:host":                   "",
+		},
+		MatchExpressions: []selectorRequirementModel{{
+			Key:      types.StringValue("tier"),
+			Operator: types.StringValue("In"),
+			Values:   []string{"frontend"},
+		}},
+	}
+
+	got := flattenSelector(expandSelector(m))
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("flattenSelector(expandSelector(m)) = %+v, want %+v", got, m)
+	}
+}
+
+// TestICMPFamilyRoundTrip checks that an ICMP field without family, which
+// the CRD defaults to IPv4, reads back as planned.
+func TestICMPFamilyRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	fields := icmpsAttribute().NestedObject.Attributes["fields"].(schema.ListNestedAttribute)
+	family := fields.NestedObject.Attributes["family"].(schema.StringAttribute)
+	if !family.Computed {
+		t.Fatal("family must be computed to hold the default of the CRD")
+	}
+	plan := planmodifier.StringResponse{PlanValue: types.StringUnknown()}
+	for _, modifier := range family.PlanModifiers {
+		modifier.PlanModifyString(ctx, planmodifier.StringRequest{ConfigValue: types.StringNull(), PlanValue: plan.PlanValue}, &plan)
+	}
+
+	planned := []icmpRuleModel{{Fields: []icmpFieldModel{{Family: plan.PlanValue, Type: types.Int64Value(8)}}}}
+	rules, err := expandICMPRules(planned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Store the rules the way the API server does, applying the CRD
+	// default to fields without family.
+	for i := range rules {
+		for j := range rules[i].Fields {
+			if rules[i].Fields[j].Family == "" {
+				rules[i].Fields[j].Family = api.IPv4Family
+			}
+		}
+	}
+	if got := flattenICMPRules(rules); !reflect.DeepEqual(got, planned) {
+		t.Errorf("flattenICMPRules() = %+v, want %+v", got, planned)
+	}
+}
+
+func TestExpandRuleInvalidICMPType(t *testing.T) {
+	m := &ruleModel{
+		EndpointSelector: &selectorModel{},
+		Ingress: []ingressRuleModel{{
+			ICMPs: []icmpRuleModel{{Fields: []icmpFieldModel{{Type: types.Int64Value(300)}}}},
+		}},
+	}
+	if _, err := expandRule(m); err == nil {
+		t.Error("expected an error for an out of range ICMP type")
+	}
+}
+
+func TestExpandPolicyRulesSanitizes(t *testing.T) {
+	if _, _, err := expandPolicyRules(&ruleModel{}, nil); err == nil {
+		t.Error("expected a rule without selector to be rejected")
+	}
+	if _, _, err := expandPolicyRules(&ruleModel{EndpointSelector: &selectorModel{}}, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestParseNamespacedID(t *testing.T) {
+	tests := []struct {
+		id              string
+		namespace, name string
+		wantErr         bool
+	}{
+		{id: "netpols/fqdn", namespace: "netpols", name: "fqdn"},
+		{id: "fqdn", namespace: defaultNamespace, name: "fqdn"},
+		{id: "", wantErr: true},
+		{id: "netpols/", wantErr: true},
+		{id: "a/b/c", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		namespace, name, err := parseNamespacedID(tt.id)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseNamespacedID(%q) error = %v, wantErr %v", tt.id, err, tt.wantErr)
+			continue
+		}
+		if namespace != tt.namespace || name != tt.name {
+			t.Errorf("parseNamespacedID(%q) = %q, %q, want %q, %q", tt.id, namespace, name, tt.namespace, tt.name)
+		}
+	}
+}
+
+func TestHostPolicyRoundTrip(t *testing.T) {
+	data := []byte(`
+nodeSelector:
+  matchLabels:
+    node-role.kubernetes.io/control-plane: ""
+ingress:
+- fromEntities:
+  - cluster
+  toPorts:
+  - ports:
+    - port: "6443"
+      protocol: TCP
+`)
+	var rule api.Rule
+	if err := yaml.Unmarshal(data, &rule); err != nil {
+		t.Fatal(err)
+	}
+
+	m := flattenRule(&rule)
+	if m.EndpointSelector != nil || m.NodeSelector == nil {
+		t.Fatalf("expected only node_selector to be set, got %+v", m)
+	}
+	if _, ok := m.NodeSelector.MatchLabels["node-role.kubernetes.io/control-plane"]; !ok {
+		t.Errorf("unexpected node_selector labels %v", m.NodeSelector.MatchLabels)
+	}
+
+	expanded, err := expandRule(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := expanded.Sanitize(); err != nil {
+		t.Fatal(err)
+	}
+	want, _ := json.Marshal(&rule)
+	got, _ := json.Marshal(expanded)
+	if string(got) != string(want) {
+		t.Errorf("round trip mismatch:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestValidateRuleSelectors(t *testing.T) {
+	selectorType := types.ObjectType{AttrTypes: map[string]attr.Type{
+		"match_labels": types.MapType{ElemType: types.StringType},
+	}}
+	selector := types.ObjectValueMust(selectorType.AttrTypes, map[string]attr.Value{
+		"match_labels": types.MapNull(types.StringType),
+	})
+	rule := func(endpointSelector, nodeSelector attr.Value) types.Object {
+		return types.ObjectValueMust(
+			map[string]attr.Type{"endpoint_selector": selectorType, "node_selector": selectorType},
+			map[string]attr.Value{"endpoint_selector": endpointSelector, "node_selector": nodeSelector},
+		)
+	}
+	null := types.ObjectNull(selectorType.AttrTypes)
+
+	tests := []struct {
+		name        string
+		rule        types.Object
+		clusterwide bool
+		wantErr     bool
+	}{
+		{"namespaced endpoint selector", rule(selector, null), false, false},
+		{"namespaced node selector", rule(null, selector), false, true},
+		{"namespaced without selector", rule(null, null), false, true},
+		{"clusterwide endpoint selector", rule(selector, null), true, false},
+		{"clusterwide node selector", rule(null, selector), true, false},
+		{"clusterwide without selector", rule(null, null), true, true},
+		{"clusterwide both selectors", rule(selector, selector), true, true},
+		{"unknown selector", rule(types.ObjectUnknown(selectorType.AttrTypes), null), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			validateRuleSelectors(tt.rule, path.Root("spec"), tt.clusterwide, &diags)
+			if diags.HasError() != tt.wantErr {
+				t.Errorf("got diagnostics %v, wantErr %v", diags, tt.wantErr)
+			}
+		})
+	}
+}
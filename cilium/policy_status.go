@@ -0,0 +1,100 @@
+package cilium
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+)
+
+// policyStatusModel maps the status of a CiliumNetworkPolicy or
+// CiliumClusterwideNetworkPolicy, as reported by the agents.
+type policyStatusModel struct {
+	Nodes map[string]policyNodeStatusModel `tfsdk:"nodes"`
+}
+
+type policyNodeStatusModel struct {
+	OK          types.Bool        `tfsdk:"ok"`
+	Enforcing   types.Bool        `tfsdk:"enforcing"`
+	Error       types.String      `tfsdk:"error"`
+	LastUpdated types.String      `tfsdk:"last_updated"`
+	Revision    types.Int64       `tfsdk:"revision"`
+	Annotations map[string]string `tfsdk:"annotations"`
+}
+
+// policyStatusAttribute returns the schema of the status of a policy.
+func policyStatusAttribute() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Description: "Status of the policy as reported by the Cilium agents.",
+		Computed:    true,
+		Attributes: map[string]schema.Attribute{
+			"nodes": schema.MapNestedAttribute{
+				Description: "Status of the policy on each node, keyed by node name.",
+				Computed:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"ok": schema.BoolAttribute{
+							Description: "Whether the policy was imported successfully by the agent.",
+							Computed:    true,
+						},
+						"enforcing": schema.BoolAttribute{
+							Description: "Whether the policy is enforced on the node.",
+							Computed:    true,
+						},
+						"error": schema.StringAttribute{
+							Description: "Error encountered by the agent while importing the policy.",
+							Computed:    true,
+						},
+						"last_updated": schema.StringAttribute{
+							Description: "Time of the last status update in RFC 3339 format.",
+							Computed:    true,
+						},
+						"revision": schema.Int64Attribute{
+							Description: "Revision of the policy repository of the agent which first enforced the policy.",
+							Computed:    true,
+						},
+						"annotations": schema.MapAttribute{
+							Description: "Annotations of the policy the status was reported for.",
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func flattenPolicyStatus(status ciliumv2.CiliumNetworkPolicyStatus) *policyStatusModel {
+	m := &policyStatusModel{}
+	if len(status.Nodes) == 0 {
+		return m
+	}
+	m.Nodes = make(map[string]policyNodeStatusModel, len(status.Nodes))
+	for node, s := range status.Nodes {
+		nodeStatus := policyNodeStatusModel{
+			OK:          types.BoolValue(s.OK),
+			Enforcing:   types.BoolValue(s.Enforcing),
+			Error:       stringValueOrNull(s.Error),
+			Revision:    types.Int64Value(int64(s.Revision)),
+			Annotations: nilIfEmptyMap(s.Annotations),
+		}
+		if !s.LastUpdated.IsZero() {
+			nodeStatus.LastUpdated = types.StringValue(s.LastUpdated.UTC().Format(time.RFC3339))
+		}
+		m.Nodes[node] = nodeStatus
+	}
+	return m
+}
+
+// policyStatusObject converts the status of a policy into the value of the
+// status attribute of the policy resources. The whole attribute is unknown
+// until the policy is applied, so the resource models hold it as an object.
+func policyStatusObject(ctx context.Context, status ciliumv2.CiliumNetworkPolicyStatus) (types.Object, diag.Diagnostics) {
+	attrTypes := policyStatusAttribute().GetType().(types.ObjectType).AttrTypes
+	return types.ObjectValueFrom(ctx, attrTypes, flattenPolicyStatus(status))
+}
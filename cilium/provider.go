@@ -2,9 +2,14 @@ package cilium
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
@@ -12,23 +17,32 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
-	"github.com/mitchellh/go-homedir"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 
 	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
 	ciliumv2alpha1 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2alpha1"
 	ciliumClientset "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth" // Register all auth providers (azure, gcp, oidc, openstack, ..).
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
 	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/client-go/util/retry"
 )
 
 var (
-	_ provider.Provider = &ciliumProvider{}
+	_ provider.Provider                   = &ciliumProvider{}
+	_ provider.ProviderWithValidateConfig = &ciliumProvider{}
 )
 
 func New() provider.Provider {
@@ -38,7 +52,88 @@ func New() provider.Provider {
 type ciliumProvider struct{}
 
 type ciliumProviderModel struct {
-	KubeConfig types.String `tfsdk:"kube_config"`
+	KubeConfig            types.String `tfsdk:"kube_config"`
+	ConfigContext         types.String `tfsdk:"config_context"`
+	ConfigContextCluster  types.String `tfsdk:"config_context_cluster"`
+	ConfigContextAuthInfo types.String `tfsdk:"config_context_auth_info"`
+	Host                  types.String `tfsdk:"host"`
+	Token                 types.String `tfsdk:"token"`
+	ClientCertificate     types.String `tfsdk:"client_certificate"`
+	ClientKey             types.String `tfsdk:"client_key"`
+	ClusterCACertificate  types.String `tfsdk:"cluster_ca_certificate"`
+	Insecure              types.Bool   `tfsdk:"insecure"`
+	InCluster             types.Bool   `tfsdk:"in_cluster"`
+	Exec                  *execModel   `tfsdk:"exec"`
+}
+
+// execModel maps the exec credential plugin block. Args and Env are kept as
+// framework values as they, or their elements, are commonly unknown during
+// plan, e.g. the name of an EKS cluster created in the same apply.
+type execModel struct {
+	APIVersion types.String `tfsdk:"api_version"`
+	Command    types.String `tfsdk:"command"`
+	Args       types.List   `tfsdk:"args"`
+	Env        types.Map    `tfsdk:"env"`
+}
+
+// isUnknown reports whether any value of the exec block is not known yet.
+func (m *execModel) isUnknown() bool {
+	if m.APIVersion.IsUnknown() || m.Command.IsUnknown() || m.Args.IsUnknown() || m.Env.IsUnknown() {
+		return true
+	}
+	for _, arg := range m.Args.Elements() {
+		if arg.IsUnknown() {
+			return true
+		}
+	}
+	for _, value := range m.Env.Elements() {
+		if value.IsUnknown() {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientOptions selects the kubeconfig and the context within it that
+// NewClient connects with. Empty fields fall back to the kubeconfig defaults.
+type ClientOptions struct {
+	// KubeConfig is the explicit kubeconfig path. When empty the
+	// KUBECONFIG environment variable and ~/.kube/config are used.
+	KubeConfig string
+
+	// Context overrides the kubeconfig current-context.
+	Context string
+
+	// ClusterName overrides the cluster of the selected context.
+	ClusterName string
+
+	// AuthInfo overrides the user of the selected context.
+	AuthInfo string
+
+	// Host is the Kubernetes API server URL. When set, the client is built
+	// from the inline credentials below and no kubeconfig is read.
+	Host string
+
+	// Token is a bearer token used to authenticate against Host.
+	Token string
+
+	// ClientCertificate and ClientKey are PEM encoded client TLS credentials.
+	ClientCertificate string
+	ClientKey         string
+
+	// ClusterCACertificate is the PEM encoded CA bundle of Host.
+	ClusterCACertificate string
+
+	// Insecure disables verification of the server certificate.
+	Insecure bool
+
+	// Exec configures a client-go credential plugin which is invoked to
+	// obtain, and transparently refresh, credentials for Host.
+	Exec *clientcmdapi.ExecConfig
+
+	// InCluster builds the client from the service account of the pod the
+	// provider runs in.
+	InCluster bool
 }
 
 type CiliumClient struct {
@@ -47,18 +142,105 @@ type CiliumClient struct {
 	CiliumClientset  ciliumClientset.Interface
 	Config           *rest.Config
 	RawConfig        clientcmdapi.Config
-	restClientGetter genericclioptions.RESTClientGetter
 	contextName      string
+
+	// restMapper maps kinds to resources from a discovery cache which is
+	// shared by all the manifest operations of the client, and reset when a
+	// kind is not found in it, for instance because its CRD was just
+	// installed.
+	restMapper apimeta.ResettableRESTMapper
+
+	// mu guards connect, which fills the fields above on first use when the
+	// client was created with newDeferredClient.
+	mu      sync.Mutex
+	connect func(context.Context) (*CiliumClient, error)
 }
 
-func NewClient(contextName, kubeconfig string) (*CiliumClient, error) {
-	// Register the Cilium types in the default scheme.
-	_ = ciliumv2.AddToScheme(scheme.Scheme)
-	_ = ciliumv2alpha1.AddToScheme(scheme.Scheme)
+// newDeferredClient returns a CiliumClient which is only connected when one
+// of its methods is first called. This lets the provider be configured with
+// values, such as the endpoint of a cluster created in the same apply, which
+// are not known until the resources they depend on have been applied.
+func newDeferredClient(connect func(context.Context) (*CiliumClient, error)) *CiliumClient {
+	return &CiliumClient{connect: connect}
+}
+
+// ensureConnected creates the underlying clients of a deferred CiliumClient.
+// Failures are not cached so that a later call can succeed once, for
+// example, the kubeconfig file has been written.
+func (c *CiliumClient) ensureConnected(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.connect == nil {
+		return nil
+	}
+
+	tflog.Debug(ctx, "Creating kubernetes client")
+	client, err := c.connect(ctx)
+	if err != nil {
+		return &clientConfigError{err: err}
+	}
+
+	c.Clientset = client.Clientset
+	c.DynamicClientset = client.DynamicClientset
+	c.CiliumClientset = client.CiliumClientset
+	c.Config = client.Config
+	c.RawConfig = client.RawConfig
+	c.restMapper = client.restMapper
+	c.contextName = client.contextName
+	c.connect = nil
+
+	tflog.Info(ctx, "Configured Kubernetes client", map[string]any{"success": true, "context": c.contextName})
+	return nil
+}
+
+// clusterIdentity returns the address of the API server the client is
+// connected to, which tells apart the objects listed from different clusters.
+func (c *CiliumClient) clusterIdentity() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.Config == nil {
+		return ""
+	}
+	return c.Config.Host
+}
 
-	restClientGetter := genericclioptions.ConfigFlags{
-		Context:    &contextName,
-		KubeConfig: &kubeconfig,
+var registerSchemeOnce sync.Once
+
+// registerCiliumScheme registers the Cilium types in the default scheme.
+func registerCiliumScheme() {
+	registerSchemeOnce.Do(func() {
+		_ = ciliumv2.AddToScheme(scheme.Scheme)
+		_ = ciliumv2alpha1.AddToScheme(scheme.Scheme)
+	})
+}
+
+func NewClient(opts ClientOptions) (*CiliumClient, error) {
+	registerCiliumScheme()
+
+	contextName := opts.Context
+	var restClientGetter genericclioptions.RESTClientGetter
+	switch {
+	case opts.InCluster:
+		inClusterConfig, err := rest.InClusterConfig()
+		if err != nil {
+			return nil, err
+		}
+		restClientGetter = &clientConfigGetter{
+			clientConfig: clientcmd.NewDefaultClientConfig(inClusterRawConfig(inClusterConfig), &clientcmd.ConfigOverrides{}),
+		}
+	case opts.Host != "":
+		restClientGetter = &clientConfigGetter{
+			clientConfig: clientcmd.NewDefaultClientConfig(inlineRawConfig(opts), &clientcmd.ConfigOverrides{}),
+		}
+	default:
+		restClientGetter = &genericclioptions.ConfigFlags{
+			Context:      &opts.Context,
+			ClusterName:  &opts.ClusterName,
+			AuthInfoName: &opts.AuthInfo,
+			KubeConfig:   &opts.KubeConfig,
+		}
 	}
 	rawKubeConfigLoader := restClientGetter.ToRawKubeConfigLoader()
 
@@ -87,6 +269,11 @@ func NewClient(contextName, kubeconfig string) (*CiliumClient, error) {
 		return nil, err
 	}
 
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
 	if contextName == "" {
 		contextName = rawConfig.CurrentContext
 	}
@@ -97,23 +284,533 @@ func NewClient(contextName, kubeconfig string) (*CiliumClient, error) {
 		Config:           config,
 		DynamicClientset: dynamicClientset,
 		RawConfig:        rawConfig,
-		restClientGetter: &restClientGetter,
 		contextName:      contextName,
+		restMapper:       restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discoveryClient)),
 	}, nil
 }
 
-func (c *CiliumClient) ListCiliumNodes(ctx context.Context) (*ciliumv2.CiliumNodeList, error) {
-	return c.CiliumClientset.CiliumV2().CiliumNodes().List(ctx, metav1.ListOptions{})
+// ListCiliumNodes lists the CiliumNodes matching opts. When opts.Limit is
+// set, the nodes are requested in pages of that size, following the continue
+// token of each page, so that large clusters are not listed in one response.
+func (c *CiliumClient) ListCiliumNodes(ctx context.Context, opts metav1.ListOptions) (*ciliumv2.CiliumNodeList, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	list := &ciliumv2.CiliumNodeList{}
+	for {
+		page, err := c.CiliumClientset.CiliumV2().CiliumNodes().List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, page.Items...)
+		list.ResourceVersion = page.ResourceVersion
+		if page.Continue == "" {
+			return list, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+func (c *CiliumClient) GetCiliumNode(ctx context.Context, name string) (*ciliumv2.CiliumNode, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumNodes().Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *CiliumClient) CreateCiliumNode(ctx context.Context, node *ciliumv2.CiliumNode) (*ciliumv2.CiliumNode, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumNodes().Create(ctx, node, metav1.CreateOptions{FieldManager: fieldManager})
+}
+
+// UpdateCiliumNode fetches the latest version of the node, applies mutate to
+// it and writes it back, retrying on conflicts with the agent and operator.
+func (c *CiliumClient) UpdateCiliumNode(ctx context.Context, name string, mutate func(*ciliumv2.CiliumNode)) (*ciliumv2.CiliumNode, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	client := c.CiliumClientset.CiliumV2().CiliumNodes()
+
+	var updated *ciliumv2.CiliumNode
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		node, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(node)
+		updated, err = client.Update(ctx, node, metav1.UpdateOptions{FieldManager: fieldManager})
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumNode(ctx context.Context, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumNodes().Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// GetNode returns the Kubernetes node of the given name.
+func (c *CiliumClient) GetNode(ctx context.Context, name string) (*corev1.Node, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.Clientset.CoreV1().Nodes().Get(ctx, name, metav1.GetOptions{})
 }
 
 func (c *CiliumClient) ListCiliumNetworkPolicies(ctx context.Context, namespace string, opts metav1.ListOptions) (*ciliumv2.CiliumNetworkPolicyList, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
 	return c.CiliumClientset.CiliumV2().CiliumNetworkPolicies(namespace).List(ctx, opts)
 }
 
 func (c *CiliumClient) ListCiliumClusterwideNetworkPolicies(ctx context.Context, opts metav1.ListOptions) (*ciliumv2.CiliumClusterwideNetworkPolicyList, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
 	return c.CiliumClientset.CiliumV2().CiliumClusterwideNetworkPolicies().List(ctx, opts)
 }
 
+func (c *CiliumClient) GetCiliumNetworkPolicy(ctx context.Context, namespace, name string) (*ciliumv2.CiliumNetworkPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumNetworkPolicies(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *CiliumClient) CreateCiliumNetworkPolicy(ctx context.Context, cnp *ciliumv2.CiliumNetworkPolicy) (*ciliumv2.CiliumNetworkPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumNetworkPolicies(cnp.Namespace).Create(ctx, cnp, metav1.CreateOptions{FieldManager: fieldManager})
+}
+
+// UpdateCiliumNetworkPolicy fetches the latest version of the policy, applies
+// mutate to it and writes it back, retrying on conflicts.
+func (c *CiliumClient) UpdateCiliumNetworkPolicy(ctx context.Context, namespace, name string, mutate func(*ciliumv2.CiliumNetworkPolicy)) (*ciliumv2.CiliumNetworkPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	client := c.CiliumClientset.CiliumV2().CiliumNetworkPolicies(namespace)
+
+	var updated *ciliumv2.CiliumNetworkPolicy
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cnp, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(cnp)
+		updated, err = client.Update(ctx, cnp, metav1.UpdateOptions{FieldManager: fieldManager})
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumNetworkPolicy(ctx context.Context, namespace, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumNetworkPolicies(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *CiliumClient) GetCiliumClusterwideNetworkPolicy(ctx context.Context, name string) (*ciliumv2.CiliumClusterwideNetworkPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumClusterwideNetworkPolicies().Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *CiliumClient) CreateCiliumClusterwideNetworkPolicy(ctx context.Context, ccnp *ciliumv2.CiliumClusterwideNetworkPolicy) (*ciliumv2.CiliumClusterwideNetworkPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumClusterwideNetworkPolicies().Create(ctx, ccnp, metav1.CreateOptions{FieldManager: fieldManager})
+}
+
+// UpdateCiliumClusterwideNetworkPolicy fetches the latest version of the
+// policy, applies mutate to it and writes it back, retrying on conflicts.
+func (c *CiliumClient) UpdateCiliumClusterwideNetworkPolicy(ctx context.Context, name string, mutate func(*ciliumv2.CiliumClusterwideNetworkPolicy)) (*ciliumv2.CiliumClusterwideNetworkPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	client := c.CiliumClientset.CiliumV2().CiliumClusterwideNetworkPolicies()
+
+	var updated *ciliumv2.CiliumClusterwideNetworkPolicy
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ccnp, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(ccnp)
+		updated, err = client.Update(ctx, ccnp, metav1.UpdateOptions{FieldManager: fieldManager})
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumClusterwideNetworkPolicy(ctx context.Context, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumClusterwideNetworkPolicies().Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *CiliumClient) GetCiliumEgressGatewayPolicy(ctx context.Context, name string) (*ciliumv2.CiliumEgressGatewayPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumEgressGatewayPolicies().Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *CiliumClient) CreateCiliumEgressGatewayPolicy(ctx context.Context, cegp *ciliumv2.CiliumEgressGatewayPolicy) (*ciliumv2.CiliumEgressGatewayPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumEgressGatewayPolicies().Create(ctx, cegp, metav1.CreateOptions{FieldManager: fieldManager})
+}
+
+// UpdateCiliumEgressGatewayPolicy fetches the latest version of the policy,
+// applies mutate to it and writes it back, retrying on conflicts.
+func (c *CiliumClient) UpdateCiliumEgressGatewayPolicy(ctx context.Context, name string, mutate func(*ciliumv2.CiliumEgressGatewayPolicy)) (*ciliumv2.CiliumEgressGatewayPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	client := c.CiliumClientset.CiliumV2().CiliumEgressGatewayPolicies()
+
+	var updated *ciliumv2.CiliumEgressGatewayPolicy
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cegp, err := client.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		mutate(cegp)
+		updated, err = client.Update(ctx, cegp, metav1.UpdateOptions{FieldManager: fieldManager})
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumEgressGatewayPolicy(ctx context.Context, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumEgressGatewayPolicies().Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// ListCiliumEndpoints lists the CiliumEndpoints of namespace, or of all
+// namespaces when it is empty, matching opts. Like ListCiliumNodes, it
+// follows the continue token of each page when opts.Limit is set.
+func (c *CiliumClient) ListCiliumEndpoints(ctx context.Context, namespace string, opts metav1.ListOptions) (*ciliumv2.CiliumEndpointList, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	list := &ciliumv2.CiliumEndpointList{}
+	for {
+		page, err := c.CiliumClientset.CiliumV2().CiliumEndpoints(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, page.Items...)
+		list.ResourceVersion = page.ResourceVersion
+		if page.Continue == "" {
+			return list, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+func (c *CiliumClient) GetCiliumEndpoint(ctx context.Context, namespace, name string) (*ciliumv2.CiliumEndpoint, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumEndpoints(namespace).Get(ctx, name, metav1.GetOptions{})
+}
+
+// ListCiliumIdentities lists the CiliumIdentities matching opts, following
+// the continue token of each page when opts.Limit is set.
+func (c *CiliumClient) ListCiliumIdentities(ctx context.Context, opts metav1.ListOptions) (*ciliumv2.CiliumIdentityList, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	list := &ciliumv2.CiliumIdentityList{}
+	for {
+		page, err := c.CiliumClientset.CiliumV2().CiliumIdentities().List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, page.Items...)
+		list.ResourceVersion = page.ResourceVersion
+		if page.Continue == "" {
+			return list, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+func (c *CiliumClient) GetCiliumIdentity(ctx context.Context, name string) (*ciliumv2.CiliumIdentity, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	return c.CiliumClientset.CiliumV2().CiliumIdentities().Get(ctx, name, metav1.GetOptions{})
+}
+
+// ciliumLocalRedirectPolicies is the API resource of CiliumLocalRedirectPolicies.
+// They are read and written with the dynamic client so that the fields of
+// ciliumLocalRedirectPolicy missing from the Cilium API types are kept.
+var ciliumLocalRedirectPolicies = ciliumv2.SchemeGroupVersion.WithResource(ciliumv2.CLRPPluralName)
+
+func (c *CiliumClient) GetCiliumLocalRedirectPolicy(ctx context.Context, namespace, name string) (*ciliumLocalRedirectPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	obj, err := c.DynamicClientset.Resource(ciliumLocalRedirectPolicies).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return localRedirectPolicyFromUnstructured(obj)
+}
+
+func (c *CiliumClient) CreateCiliumLocalRedirectPolicy(ctx context.Context, clrp *ciliumLocalRedirectPolicy) (*ciliumLocalRedirectPolicy, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	obj, err := clrp.toUnstructured()
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.DynamicClientset.Resource(ciliumLocalRedirectPolicies).Namespace(clrp.Namespace).Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, err
+	}
+	return localRedirectPolicyFromUnstructured(created)
+}
+
+// UpdateCiliumLocalRedirectPolicy applies mutate to the current version of
+// the policy and writes it back, retrying on conflicts.
+func (c *CiliumClient) UpdateCiliumLocalRedirectPolicy(ctx context.Context, namespace, name string, mutate func(*ciliumLocalRedirectPolicy)) (*ciliumLocalRedirectPolicy, error) {
+	var updated *ciliumLocalRedirectPolicy
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		clrp, err := c.GetCiliumLocalRedirectPolicy(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		mutate(clrp)
+		obj, err := clrp.toUnstructured()
+		if err != nil {
+			return err
+		}
+		obj, err = c.DynamicClientset.Resource(ciliumLocalRedirectPolicies).Namespace(namespace).Update(ctx, obj, metav1.UpdateOptions{FieldManager: fieldManager})
+		if err != nil {
+			return err
+		}
+		updated, err = localRedirectPolicyFromUnstructured(obj)
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumLocalRedirectPolicy(ctx context.Context, namespace, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.DynamicClientset.Resource(ciliumLocalRedirectPolicies).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// ciliumEnvoyConfigs and ciliumClusterwideEnvoyConfigs are the API
+// resources of Envoy configurations, read and written with the dynamic
+// client like ciliumLocalRedirectPolicies.
+var (
+	ciliumEnvoyConfigs            = ciliumv2.SchemeGroupVersion.WithResource(ciliumv2.CECPluralName)
+	ciliumClusterwideEnvoyConfigs = ciliumv2.SchemeGroupVersion.WithResource(ciliumv2.CCECPluralName)
+)
+
+func (c *CiliumClient) GetCiliumEnvoyConfig(ctx context.Context, namespace, name string) (*ciliumEnvoyConfig, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	obj, err := c.DynamicClientset.Resource(ciliumEnvoyConfigs).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return envoyConfigFromUnstructured(obj)
+}
+
+func (c *CiliumClient) CreateCiliumEnvoyConfig(ctx context.Context, cec *ciliumEnvoyConfig) (*ciliumEnvoyConfig, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	obj, err := cec.toUnstructured(ciliumv2.CECKindDefinition)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.DynamicClientset.Resource(ciliumEnvoyConfigs).Namespace(cec.Namespace).Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, err
+	}
+	return envoyConfigFromUnstructured(created)
+}
+
+// UpdateCiliumEnvoyConfig applies mutate to the current version of the
+// configuration and writes it back, retrying on conflicts.
+func (c *CiliumClient) UpdateCiliumEnvoyConfig(ctx context.Context, namespace, name string, mutate func(*ciliumEnvoyConfig)) (*ciliumEnvoyConfig, error) {
+	var updated *ciliumEnvoyConfig
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cec, err := c.GetCiliumEnvoyConfig(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		mutate(cec)
+		obj, err := cec.toUnstructured(ciliumv2.CECKindDefinition)
+		if err != nil {
+			return err
+		}
+		obj, err = c.DynamicClientset.Resource(ciliumEnvoyConfigs).Namespace(namespace).Update(ctx, obj, metav1.UpdateOptions{FieldManager: fieldManager})
+		if err != nil {
+			return err
+		}
+		updated, err = envoyConfigFromUnstructured(obj)
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumEnvoyConfig(ctx context.Context, namespace, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.DynamicClientset.Resource(ciliumEnvoyConfigs).Namespace(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+func (c *CiliumClient) GetCiliumClusterwideEnvoyConfig(ctx context.Context, name string) (*ciliumEnvoyConfig, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	obj, err := c.DynamicClientset.Resource(ciliumClusterwideEnvoyConfigs).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return envoyConfigFromUnstructured(obj)
+}
+
+func (c *CiliumClient) CreateCiliumClusterwideEnvoyConfig(ctx context.Context, ccec *ciliumEnvoyConfig) (*ciliumEnvoyConfig, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, err
+	}
+	obj, err := ccec.toUnstructured(ciliumv2.CCECKindDefinition)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.DynamicClientset.Resource(ciliumClusterwideEnvoyConfigs).Create(ctx, obj, metav1.CreateOptions{FieldManager: fieldManager})
+	if err != nil {
+		return nil, err
+	}
+	return envoyConfigFromUnstructured(created)
+}
+
+// UpdateCiliumClusterwideEnvoyConfig applies mutate to the current version
+// of the configuration and writes it back, retrying on conflicts.
+func (c *CiliumClient) UpdateCiliumClusterwideEnvoyConfig(ctx context.Context, name string, mutate func(*ciliumEnvoyConfig)) (*ciliumEnvoyConfig, error) {
+	var updated *ciliumEnvoyConfig
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		ccec, err := c.GetCiliumClusterwideEnvoyConfig(ctx, name)
+		if err != nil {
+			return err
+		}
+		mutate(ccec)
+		obj, err := ccec.toUnstructured(ciliumv2.CCECKindDefinition)
+		if err != nil {
+			return err
+		}
+		obj, err = c.DynamicClientset.Resource(ciliumClusterwideEnvoyConfigs).Update(ctx, obj, metav1.UpdateOptions{FieldManager: fieldManager})
+		if err != nil {
+			return err
+		}
+		updated, err = envoyConfigFromUnstructured(obj)
+		return err
+	})
+	return updated, err
+}
+
+func (c *CiliumClient) DeleteCiliumClusterwideEnvoyConfig(ctx context.Context, name string) error {
+	if err := c.ensureConnected(ctx); err != nil {
+		return err
+	}
+	return c.DynamicClientset.Resource(ciliumClusterwideEnvoyConfigs).Delete(ctx, name, metav1.DeleteOptions{})
+}
+
+// identityUsage returns the number of CiliumEndpoints of the cluster using
+// each numeric identity.
+func (c *CiliumClient) identityUsage(ctx context.Context) (map[int64]int64, error) {
+	endpoints, err := c.ListCiliumEndpoints(ctx, "", metav1.ListOptions{Limit: defaultCiliumEndpointPageSize})
+	if err != nil {
+		return nil, err
+	}
+	usage := make(map[int64]int64)
+	for _, cep := range endpoints.Items {
+		if cep.Status.Identity != nil {
+			usage[cep.Status.Identity.ID]++
+		}
+	}
+	return usage, nil
+}
+
+// dynamicResource returns a dynamic client for the objects of the given kind,
+// scoped to namespace when the kind is namespaced. An empty namespace of a
+// namespaced kind is replaced by the default namespace.
+func (c *CiliumClient) dynamicResource(ctx context.Context, gvk k8sschema.GroupVersionKind, namespace string) (dynamic.ResourceInterface, string, error) {
+	if err := c.ensureConnected(ctx); err != nil {
+		return nil, "", err
+	}
+	mapping, err := c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if apimeta.IsNoMatchError(err) {
+		c.restMapper.Reset()
+		mapping, err = c.restMapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	if mapping.Scope.Name() != apimeta.RESTScopeNameNamespace {
+		return c.DynamicClientset.Resource(mapping.Resource), "", nil
+	}
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return c.DynamicClientset.Resource(mapping.Resource).Namespace(namespace), namespace, nil
+}
+
+// ApplyManifest creates or updates obj with server-side apply, taking
+// ownership of the fields it sets.
+func (c *CiliumClient) ApplyManifest(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	client, namespace, err := c.dynamicResource(ctx, obj.GroupVersionKind(), obj.GetNamespace())
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	obj.SetNamespace(namespace)
+	return client.Apply(ctx, obj.GetName(), obj, metav1.ApplyOptions{FieldManager: fieldManager, Force: true})
+}
+
+func (c *CiliumClient) GetManifest(ctx context.Context, gvk k8sschema.GroupVersionKind, namespace, name string) (*unstructured.Unstructured, error) {
+	client, _, err := c.dynamicResource(ctx, gvk, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return client.Get(ctx, name, metav1.GetOptions{})
+}
+
+func (c *CiliumClient) DeleteManifest(ctx context.Context, gvk k8sschema.GroupVersionKind, namespace, name string) error {
+	client, _, err := c.dynamicResource(ctx, gvk, namespace)
+	if err != nil {
+		return err
+	}
+	return client.Delete(ctx, name, metav1.DeleteOptions{})
+}
+
 // Metadata should return the metadata for the provider, such as
 // a type name and version data.
 //
@@ -130,12 +827,211 @@ func (hp *ciliumProvider) Schema(_ context.Context, _ provider.SchemaRequest, re
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"kube_config": schema.StringAttribute{
-				Optional: true,
+				Description: "Path to the kubeconfig file. Defaults to the KUBECONFIG environment variable, which may list several files to merge, and then to ~/.kube/config.",
+				Optional:    true,
+			},
+			"config_context": schema.StringAttribute{
+				Description: "Context to use from the kubeconfig. Defaults to the KUBE_CTX environment variable, then to the current-context.",
+				Optional:    true,
+			},
+			"config_context_cluster": schema.StringAttribute{
+				Description: "Cluster to use instead of the one referenced by the selected context. Defaults to the KUBE_CTX_CLUSTER environment variable.",
+				Optional:    true,
+			},
+			"config_context_auth_info": schema.StringAttribute{
+				Description: "User to use instead of the one referenced by the selected context. Defaults to the KUBE_CTX_AUTH_INFO environment variable.",
+				Optional:    true,
+			},
+			"host": schema.StringAttribute{
+				Description: "Kubernetes API server URL. When set, the provider authenticates with the inline credentials and does not read a kubeconfig.",
+				Optional:    true,
+			},
+			"token": schema.StringAttribute{
+				Description: "Bearer token used to authenticate against host. Conflicts with client_certificate and client_key.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"client_certificate": schema.StringAttribute{
+				Description: "PEM encoded client certificate used to authenticate against host. Requires client_key.",
+				Optional:    true,
+			},
+			"client_key": schema.StringAttribute{
+				Description: "PEM encoded private key of client_certificate.",
+				Optional:    true,
+				Sensitive:   true,
+			},
+			"cluster_ca_certificate": schema.StringAttribute{
+				Description: "PEM encoded CA bundle used to verify the certificate of host.",
+				Optional:    true,
+			},
+			"insecure": schema.BoolAttribute{
+				Description: "Skip verification of the server certificate of host. Conflicts with cluster_ca_certificate.",
+				Optional:    true,
+			},
+			"in_cluster": schema.BoolAttribute{
+				Description: "Use the service account of the pod the provider runs in. Defaults to true when KUBERNETES_SERVICE_HOST is set and neither kube_config, KUBECONFIG nor host is configured; set to false to disable the detection.",
+				Optional:    true,
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"exec": schema.SingleNestedBlock{
+				Description: "Credential plugin which is run to obtain short-lived credentials for host, such as `aws eks get-token`. Conflicts with token and client_certificate.",
+				Attributes: map[string]schema.Attribute{
+					"api_version": schema.StringAttribute{
+						Description: "API version of the ExecCredential exchanged with the plugin, e.g. client.authentication.k8s.io/v1beta1.",
+						Optional:    true,
+					},
+					"command": schema.StringAttribute{
+						Description: "Command to execute.",
+						Optional:    true,
+					},
+					"args": schema.ListAttribute{
+						Description: "Arguments passed to command.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+					"env": schema.MapAttribute{
+						Description: "Environment variables set when running command.",
+						ElementType: types.StringType,
+						Optional:    true,
+					},
+				},
 			},
 		},
 	}
 }
 
+// namedString is a string attribute of the provider configuration.
+type namedString struct {
+	name  string
+	value types.String
+}
+
+// ValidateConfig rejects combinations of credential sources which cannot be
+// used together, so they surface at plan time instead of during Configure.
+func (hp *ciliumProvider) ValidateConfig(ctx context.Context, req provider.ValidateConfigRequest, resp *provider.ValidateConfigResponse) {
+	var config ciliumProviderModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	isSet := func(value types.String) bool {
+		return value.IsUnknown() || value.ValueString() != ""
+	}
+
+	// The attributes are listed by name so that the diagnostics are
+	// reported in a stable order.
+	if config.InCluster.ValueBool() {
+		for _, attribute := range []namedString{
+			{"config_context", config.ConfigContext},
+			{"config_context_auth_info", config.ConfigContextAuthInfo},
+			{"config_context_cluster", config.ConfigContextCluster},
+			{"host", config.Host},
+			{"kube_config", config.KubeConfig},
+		} {
+			if isSet(attribute.value) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute.name),
+					"Conflicting Kubernetes Client Configuration",
+					attribute.name+" cannot be combined with in_cluster, which always uses the pod service account.",
+				)
+			}
+		}
+		return
+	}
+
+	if isSet(config.Host) {
+		for _, attribute := range []namedString{
+			{"config_context", config.ConfigContext},
+			{"config_context_auth_info", config.ConfigContextAuthInfo},
+			{"config_context_cluster", config.ConfigContextCluster},
+			{"kube_config", config.KubeConfig},
+		} {
+			if isSet(attribute.value) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute.name),
+					"Conflicting Kubernetes Client Configuration",
+					attribute.name+" cannot be combined with host. Either configure the cluster inline with host and its credentials, or load it from a kubeconfig.",
+				)
+			}
+		}
+	} else {
+		for _, attribute := range []namedString{
+			{"client_certificate", config.ClientCertificate},
+			{"client_key", config.ClientKey},
+			{"cluster_ca_certificate", config.ClusterCACertificate},
+			{"token", config.Token},
+		} {
+			if isSet(attribute.value) {
+				resp.Diagnostics.AddAttributeError(
+					path.Root(attribute.name),
+					"Missing Kubernetes API Host",
+					attribute.name+" is only used together with host. Set host to the Kubernetes API server URL.",
+				)
+			}
+		}
+		if config.Exec != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("exec"),
+				"Missing Kubernetes API Host",
+				"exec is only used together with host. Set host to the Kubernetes API server URL.",
+			)
+		}
+	}
+
+	if config.Exec != nil {
+		if config.Exec.APIVersion.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("exec").AtName("api_version"),
+				"Missing exec API Version",
+				"api_version must be set in the exec block, e.g. client.authentication.k8s.io/v1beta1.",
+			)
+		}
+		if config.Exec.Command.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("exec").AtName("command"),
+				"Missing exec Command",
+				"command must be set in the exec block.",
+			)
+		}
+		if isSet(config.Token) || isSet(config.ClientCertificate) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("exec"),
+				"Conflicting Kubernetes Credentials",
+				"exec cannot be combined with token or client_certificate. Configure exactly one way to authenticate.",
+			)
+		}
+	}
+
+	if isSet(config.Token) && (isSet(config.ClientCertificate) || isSet(config.ClientKey)) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Conflicting Kubernetes Credentials",
+			"token cannot be combined with client_certificate and client_key. Configure exactly one way to authenticate.",
+		)
+	}
+
+	if !config.ClientCertificate.IsUnknown() && !config.ClientKey.IsUnknown() &&
+		(config.ClientCertificate.ValueString() == "") != (config.ClientKey.ValueString() == "") {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("client_certificate"),
+			"Incomplete Kubernetes Client Certificate",
+			"client_certificate and client_key must be set together.",
+		)
+	}
+
+	if config.Insecure.ValueBool() && isSet(config.ClusterCACertificate) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("insecure"),
+			"Conflicting Kubernetes TLS Configuration",
+			"insecure cannot be combined with cluster_ca_certificate.",
+		)
+	}
+}
+
 // Configure is called at the beginning of the provider lifecycle, when
 // Terraform sends to the provider the values the user specified in the
 // provider configuration block. These are supplied in the
@@ -155,68 +1051,147 @@ func (hp *ciliumProvider) Configure(ctx context.Context, req provider.ConfigureR
 		return
 	}
 
-	// If practitioner provided a configuration value for any of the
-	// attributes, it must be a known value.
-	if config.KubeConfig.IsUnknown() {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("kube_config"),
-			"Unknown KubeConfig",
-			"The provider cannot create the cilium API client as there is an unknown configuration value for the cilium API host. "+
-				"Either target apply the source of the value first, set the value statically in the configuration, or use the KUBE_HOST environment variable.",
-		)
-	}
+	// Values which are unknown during plan, typically the credentials of a
+	// cluster created in the same apply, become known once Terraform calls
+	// Configure again during apply. Until then any attempt to use the
+	// client reports which attributes are still unknown.
+	if unknown := config.unknownAttributes(); len(unknown) > 0 {
+		tflog.Info(ctx, "Deferring cilium client creation until the provider configuration is known", map[string]any{"unknown": unknown})
 
-	if resp.Diagnostics.HasError() {
+		client := newDeferredClient(func(context.Context) (*CiliumClient, error) {
+			return nil, fmt.Errorf("the provider configuration depends on values which are not known yet: %s. "+
+				"They will be known during apply, or target apply the resources they come from first", strings.Join(unknown, ", "))
+		})
+		resp.DataSourceData = client
+		resp.ResourceData = client
 		return
 	}
 
-	// Default values to environment variables, but override
-	// with Terraform configuration value if set.
-	kubeConfig := os.Getenv("KUBECONFIG")
+	// Point out skipped KUBECONFIG entries now, while Terraform still shows
+	// diagnostics of the provider. A kubeconfig which cannot be resolved
+	// yet is only reported once the client is used, as it may be written
+	// during apply.
+	if config.usesKubeConfig() {
+		if source, err := resolveKubeConfig(config.KubeConfig.ValueString(), os.Getenv("KUBECONFIG")); err == nil {
+			for _, missing := range source.Missing {
+				resp.Diagnostics.AddWarning(
+					"Kubeconfig File Not Found",
+					"The file "+missing+" listed in the KUBECONFIG environment variable does not exist and was skipped.",
+				)
+			}
+		}
+	}
 
-	// If any of the expected configurations are missing, return
-	// errors with provider-specific guidance.
-	if kubeConfig == "" {
-		resp.Diagnostics.AddAttributeError(
-			path.Root("kube_config"),
-			"Missing Kubernetes kubeConfig",
-			"The provider cannot create the cilium API client as there is a missing or empty value for the cilium API password. "+
-				"Set the password value in the configuration or use the KUBECONFIG environment variable. "+
-				"If either is already set, ensure the value is not empty.",
-		)
+	// Make the cilium client available during DataSource and Resource
+	// type Configure methods. The client connects on first use.
+	client := newDeferredClient(func(ctx context.Context) (*CiliumClient, error) {
+		opts, source, err := config.clientOptions(ctx)
+		if err != nil {
+			return nil, err
+		}
+		client, err := NewClient(opts)
+		if err != nil && source != nil {
+			return nil, fmt.Errorf("unable to use the kubeconfig loaded from the %s: %w", source, err)
+		}
+		return client, err
+	})
+	resp.DataSourceData = client
+	resp.ResourceData = client
+}
+
+// unknownAttributes returns the names of the configured attributes whose
+// values are not known yet.
+func (m ciliumProviderModel) unknownAttributes() []string {
+	var unknown []string
+	for attribute, value := range map[string]attr.Value{
+		"kube_config":              m.KubeConfig,
+		"config_context":           m.ConfigContext,
+		"config_context_cluster":   m.ConfigContextCluster,
+		"config_context_auth_info": m.ConfigContextAuthInfo,
+		"host":                     m.Host,
+		"token":                    m.Token,
+		"client_certificate":       m.ClientCertificate,
+		"client_key":               m.ClientKey,
+		"cluster_ca_certificate":   m.ClusterCACertificate,
+		"insecure":                 m.Insecure,
+		"in_cluster":               m.InCluster,
+	} {
+		if value.IsUnknown() {
+			unknown = append(unknown, attribute)
+		}
+	}
+	if m.Exec != nil && m.Exec.isUnknown() {
+		unknown = append(unknown, "exec")
 	}
+	sort.Strings(unknown)
+	return unknown
+}
 
-	if resp.Diagnostics.HasError() {
-		return
+// inCluster reports whether the client is built from the pod service
+// account, either as configured or, when in_cluster is not set, because the
+// provider runs in a pod and no other source is configured.
+func (m ciliumProviderModel) inCluster() bool {
+	if m.InCluster.IsNull() && m.Host.ValueString() == "" && m.ConfigContext.ValueString() == "" {
+		return detectInCluster(m.KubeConfig.ValueString(), os.Getenv("KUBECONFIG"))
 	}
+	return m.InCluster.ValueBool()
+}
 
-	ctx = tflog.SetField(ctx, "KUBE_CONFIG", kubeConfig)
+// usesKubeConfig reports whether the client is built from a kubeconfig.
+func (m ciliumProviderModel) usesKubeConfig() bool {
+	return !m.inCluster() && m.Host.ValueString() == ""
+}
 
-	home, err := homedir.Dir()
-	if err != nil {
-		panic(err.Error())
+// clientOptions resolves the provider configuration into the options used to
+// create the CiliumClient. In order of precedence the client is built from
+// the pod service account, inline credentials or a kubeconfig, in which case
+// the kubeconfig source is returned too.
+func (m ciliumProviderModel) clientOptions(ctx context.Context) (ClientOptions, *kubeConfigSource, error) {
+	if m.inCluster() {
+		tflog.Info(ctx, "Using in-cluster service account")
+		return ClientOptions{InCluster: true}, nil, nil
 	}
-	tflog.Debug(ctx, "Creating kubernetes client")
 
-	clientset, err := NewClient("", filepath.Join(home, ".kube", "config"))
-	assertNoError(err)
+	if m.Host.ValueString() != "" {
+		opts := ClientOptions{
+			Host:                 m.Host.ValueString(),
+			Token:                m.Token.ValueString(),
+			ClientCertificate:    m.ClientCertificate.ValueString(),
+			ClientKey:            m.ClientKey.ValueString(),
+			ClusterCACertificate: m.ClusterCACertificate.ValueString(),
+			Insecure:             m.Insecure.ValueBool(),
+		}
+		if m.Exec != nil {
+			opts.Exec = newExecConfig(m.Exec)
+		}
+		tflog.Info(ctx, "Using inline credentials", map[string]any{"kube_host": opts.Host})
+		return opts, nil, nil
+	}
 
+	// Default values to environment variables, but override
+	// with Terraform configuration value if set.
+	source, err := resolveKubeConfig(m.KubeConfig.ValueString(), os.Getenv("KUBECONFIG"))
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"Unable to Create Kubernetes API CiliumClient",
-			"An unexpected error occurred when creating the cilium API client. "+
-				"If the error is not clear, please contact the provider developers.\n\n"+
-				"cilium CiliumClient Error: "+err.Error(),
-		)
-		return
+		return ClientOptions{}, nil, fmt.Errorf("%w. Set the kube_config attribute in the provider configuration or use the KUBECONFIG environment variable, "+
+			"which may contain a list of files separated by %q", err, string(filepath.ListSeparator))
 	}
 
-	// Make the cilium client available during DataSource and Resource
-	// type Configure methods.
-	resp.DataSourceData = clientset
-	resp.ResourceData = clientset
+	for _, missing := range source.Missing {
+		tflog.Warn(ctx, "Skipping kubeconfig file listed in KUBECONFIG which does not exist", map[string]any{"path": missing})
+	}
 
-	tflog.Info(ctx, "Configured Kubernetes client", map[string]any{"success": true})
+	opts := ClientOptions{
+		KubeConfig:  source.ExplicitPath(),
+		Context:     stringValueOrEnv(m.ConfigContext, "KUBE_CTX"),
+		ClusterName: stringValueOrEnv(m.ConfigContextCluster, "KUBE_CTX_CLUSTER"),
+		AuthInfo:    stringValueOrEnv(m.ConfigContextAuthInfo, "KUBE_CTX_AUTH_INFO"),
+	}
+	tflog.Info(ctx, "Using kubeconfig", map[string]any{
+		"kube_config_source": source.Origin,
+		"kube_config_paths":  source.Paths,
+		"kube_context":       opts.Context,
+	})
+	return opts, &source, nil
 }
 
 // DataSources returns a slice of functions to instantiate each DataSource
@@ -229,6 +1204,14 @@ func (hp *ciliumProvider) DataSources(_ context.Context) []func() datasource.Dat
 		NewCiliumNodeDataSource,
 		NewCiliumNetworkPolicyDataSource,
 		NewCiliumClusterwideNetworkPolicyDataSource,
+		NewCiliumNodeLookupDataSource,
+		NewCiliumNetworkPolicyLookupDataSource,
+		NewCiliumClusterwideNetworkPolicyLookupDataSource,
+		NewCiliumEndpointsDataSource,
+		NewCiliumEndpointLookupDataSource,
+		NewCiliumIdentitiesDataSource,
+		NewCiliumIdentityLookupDataSource,
+		NewEgressGatewayNodesDataSource,
 	}
 }
 
@@ -239,21 +1222,68 @@ func (hp *ciliumProvider) DataSources(_ context.Context) []func() datasource.Dat
 func (hp *ciliumProvider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewCiliumNodeResource,
+		NewCiliumNetworkPolicyResource,
+		NewCiliumClusterwideNetworkPolicyResource,
+		NewCiliumLocalRedirectPolicyResource,
+		NewCiliumEgressGatewayPolicyResource,
+		NewCiliumEnvoyConfigResource,
+		NewCiliumClusterwideEnvoyConfigResource,
+		NewCiliumManifestResource,
 	}
 }
 
-func assertNoError(err error) {
-	if err != nil {
-		panic(err)
+// stringValueOrNull maps the empty string to a null value, matching how
+// Kubernetes omits empty fields.
+func stringValueOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
 	}
+	return types.StringValue(value)
 }
 
-func assertNoErrorTF(err error, resp *datasource.ReadResponse, msg string) {
-	if err != nil {
-		resp.Diagnostics.AddError(
-			msg,
-			err.Error(),
-		)
-		return
+// nilIfEmpty maps an empty slice to nil so that it is stored as null.
+func nilIfEmpty(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+// newExecConfig converts the exec block into a client-go credential plugin
+// configuration. The plugin is never run interactively as Terraform owns the
+// terminal.
+func newExecConfig(exec *execModel) *clientcmdapi.ExecConfig {
+	config := &clientcmdapi.ExecConfig{
+		APIVersion:      exec.APIVersion.ValueString(),
+		Command:         exec.Command.ValueString(),
+		InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+	}
+	for _, arg := range exec.Args.Elements() {
+		if arg, ok := arg.(types.String); ok {
+			config.Args = append(config.Args, arg.ValueString())
+		}
+	}
+
+	env := exec.Env.Elements()
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value, ok := env[name].(types.String); ok {
+			config.Env = append(config.Env, clientcmdapi.ExecEnvVar{Name: name, Value: value.ValueString()})
+		}
+	}
+
+	return config
+}
+
+// stringValueOrEnv returns the configured value, falling back to the named
+// environment variable when the attribute is not set.
+func stringValueOrEnv(value types.String, env string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
 	}
+	return os.Getenv(env)
 }
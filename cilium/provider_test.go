@@ -1,8 +1,37 @@
 package cilium
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ciliumv2 "github.com/cilium/cilium/pkg/k8s/apis/cilium.io/v2"
+	"github.com/cilium/cilium/pkg/k8s/client/clientset/versioned"
+	ciliumfake "github.com/cilium/cilium/pkg/k8s/client/clientset/versioned/fake"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sschema "k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/yaml"
 )
 
 const (
@@ -24,3 +53,597 @@ var (
 		"cilium": providerserver.NewProtocol6WithError(New()),
 	}
 )
+
+const testKubeConfig = `
+apiVersion: v1
+kind: Config
+current-context: alpha
+clusters:
+- name: alpha
+  cluster:
+    server: https://alpha.example.com
+- name: beta
+  cluster:
+    server: https://beta.example.com
+users:
+- name: alpha
+  user:
+    token: alpha-token
+- name: beta
+  user:
+    token: beta-token
+contexts:
+- name: alpha
+  context:
+    cluster: alpha
+    user: alpha
+- name: beta
+  context:
+    cluster: beta
+    user: beta
+`
+
+func TestNewClientContextSelection(t *testing.T) {
+	kubeConfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeConfig, []byte(testKubeConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		opts  ClientOptions
+		host  string
+		token string
+	}{
+		{
+			name:  "current context",
+			opts:  ClientOptions{KubeConfig: kubeConfig},
+			host:  "https://alpha.example.com",
+			token: "alpha-token",
+		},
+		{
+			name:  "explicit context",
+			opts:  ClientOptions{KubeConfig: kubeConfig, Context: "beta"},
+			host:  "https://beta.example.com",
+			token: "beta-token",
+		},
+		{
+			name:  "cluster and user overrides",
+			opts:  ClientOptions{KubeConfig: kubeConfig, Context: "alpha", ClusterName: "beta", AuthInfo: "beta"},
+			host:  "https://beta.example.com",
+			token: "beta-token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if client.Config.Host != tt.host {
+				t.Errorf("host = %q, want %q", client.Config.Host, tt.host)
+			}
+			if client.Config.BearerToken != tt.token {
+				t.Errorf("token = %q, want %q", client.Config.BearerToken, tt.token)
+			}
+		})
+	}
+
+	if _, err := NewClient(ClientOptions{KubeConfig: kubeConfig, Context: "gamma"}); err == nil {
+		t.Error("expected an error for an unknown context")
+	}
+}
+
+func TestNewClientInlineCredentials(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		Host:     "https://inline.example.com",
+		Token:    "inline-token",
+		Insecure: true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if client.Config.Host != "https://inline.example.com" {
+		t.Errorf("host = %q", client.Config.Host)
+	}
+	if client.Config.BearerToken != "inline-token" {
+		t.Errorf("token = %q", client.Config.BearerToken)
+	}
+	if !client.Config.Insecure {
+		t.Error("expected insecure TLS configuration")
+	}
+	if client.RawConfig.CurrentContext != inlineContextName {
+		t.Errorf("raw config current context = %q", client.RawConfig.CurrentContext)
+	}
+}
+
+// testProviderConfig builds a provider configuration where every attribute
+// not listed in values is null.
+func testProviderConfig(t *testing.T, values map[string]tftypes.Value) tfsdk.Config {
+	t.Helper()
+	ctx := context.Background()
+
+	var resp provider.SchemaResponse
+	New().Schema(ctx, provider.SchemaRequest{}, &resp)
+	objectType := resp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+			continue
+		}
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	return tfsdk.Config{
+		Schema: resp.Schema,
+		Raw:    tftypes.NewValue(objectType, attributes),
+	}
+}
+
+// testReadDataSource reads d configured with values, leaving unspecified
+// attributes null, against client.
+func testReadDataSource(t *testing.T, d datasource.DataSource, client *CiliumClient, values map[string]tftypes.Value) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var configureResp datasource.ConfigureResponse
+	d.(datasource.DataSourceWithConfigure).Configure(ctx, datasource.ConfigureRequest{ProviderData: client}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatal(configureResp.Diagnostics)
+	}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		if value, ok := values[name]; ok {
+			attributes[name] = value
+			continue
+		}
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, attributes)}
+
+	resp := datasource.ReadResponse{State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)}}
+	d.Read(ctx, datasource.ReadRequest{Config: config}, &resp)
+	return resp.State, resp.Diagnostics
+}
+
+// testImportResource imports the object identified by id with r the way
+// Terraform does, calling ImportState and then Read, and returns the
+// resulting state.
+func testImportResource(t *testing.T, r resource.Resource, client *CiliumClient, id string) (tfsdk.State, diag.Diagnostics) {
+	t.Helper()
+	ctx := context.Background()
+
+	var configureResp resource.ConfigureResponse
+	r.(resource.ResourceWithConfigure).Configure(ctx, resource.ConfigureRequest{ProviderData: client}, &configureResp)
+	if configureResp.Diagnostics.HasError() {
+		t.Fatal(configureResp.Diagnostics)
+	}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	emptyState := tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil)}
+
+	importResp := resource.ImportStateResponse{State: emptyState}
+	r.(resource.ResourceWithImportState).ImportState(ctx, resource.ImportStateRequest{ID: id}, &importResp)
+	if importResp.Diagnostics.HasError() {
+		return importResp.State, importResp.Diagnostics
+	}
+
+	readResp := resource.ReadResponse{State: importResp.State}
+	r.Read(ctx, resource.ReadRequest{State: importResp.State}, &readResp)
+	return readResp.State, readResp.Diagnostics
+}
+
+// testDynamicClient returns a client whose dynamic clientset holds the
+// Cilium objects given as YAML manifests, for the kinds read and written with
+// the dynamic client.
+func testDynamicClient(t *testing.T, manifests ...string) *CiliumClient {
+	t.Helper()
+	var objects []runtime.Object
+	for _, manifest := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := yaml.Unmarshal([]byte(manifest), &obj.Object); err != nil {
+			t.Fatal(err)
+		}
+		objects = append(objects, obj)
+	}
+	listKinds := map[k8sschema.GroupVersionResource]string{
+		ciliumLocalRedirectPolicies:   ciliumv2.CLRPKindDefinition + "List",
+		ciliumEnvoyConfigs:            ciliumv2.CECKindDefinition + "List",
+		ciliumClusterwideEnvoyConfigs: ciliumv2.CCECKindDefinition + "List",
+	}
+	return &CiliumClient{DynamicClientset: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, objects...)}
+}
+
+func TestNewClientExecCredentials(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		Host: "https://exec.example.com",
+		Exec: newExecConfig(&execModel{
+			APIVersion: types.StringValue("client.authentication.k8s.io/v1beta1"),
+			Command:    types.StringValue("aws"),
+			Args: types.ListValueMust(types.StringType, []attr.Value{
+				types.StringValue("eks"), types.StringValue("get-token"), types.StringValue("--cluster-name"), types.StringValue("demo"),
+			}),
+			Env: types.MapValueMust(types.StringType, map[string]attr.Value{"AWS_PROFILE": types.StringValue("demo")}),
+		}),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	exec := client.Config.ExecProvider
+	if exec == nil {
+		t.Fatal("expected an exec provider")
+	}
+	if exec.Command != "aws" || len(exec.Args) != 4 {
+		t.Errorf("exec = %s %v", exec.Command, exec.Args)
+	}
+	if len(exec.Env) != 1 || exec.Env[0].Name != "AWS_PROFILE" || exec.Env[0].Value != "demo" {
+		t.Errorf("exec env = %v", exec.Env)
+	}
+}
+
+func TestProviderValidateConfig(t *testing.T) {
+	str := func(v string) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+	unknown := tftypes.NewValue(tftypes.String, tftypes.UnknownValue)
+	execType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"api_version": tftypes.String,
+		"command":     tftypes.String,
+		"args":        tftypes.List{ElementType: tftypes.String},
+		"env":         tftypes.Map{ElementType: tftypes.String},
+	}}
+	exec := tftypes.NewValue(execType, map[string]tftypes.Value{
+		"api_version": str("client.authentication.k8s.io/v1beta1"),
+		"command":     str("aws"),
+		"args":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{str("eks"), str("get-token")}),
+		"env":         tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, nil),
+	})
+
+	// The name of a cluster created in the same apply is unknown until
+	// apply.
+	unknownArgExec := tftypes.NewValue(execType, map[string]tftypes.Value{
+		"api_version": str("client.authentication.k8s.io/v1beta1"),
+		"command":     str("aws"),
+		"args":        tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{str("eks"), unknown}),
+		"env":         tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{"AWS_PROFILE": unknown}),
+	})
+
+	tests := []struct {
+		name    string
+		values  map[string]tftypes.Value
+		wantErr bool
+	}{
+		{
+			name:   "kubeconfig only",
+			values: map[string]tftypes.Value{"kube_config": str("~/.kube/config"), "config_context": str("kind")},
+		},
+		{
+			name:   "host with token",
+			values: map[string]tftypes.Value{"host": str("https://k8s"), "token": str("t"), "cluster_ca_certificate": str("ca")},
+		},
+		{
+			name:   "host with client certificate",
+			values: map[string]tftypes.Value{"host": str("https://k8s"), "client_certificate": str("c"), "client_key": str("k")},
+		},
+		{
+			name:   "unknown host with unknown token",
+			values: map[string]tftypes.Value{"host": unknown, "token": unknown},
+		},
+		{
+			name:   "host with exec",
+			values: map[string]tftypes.Value{"host": str("https://k8s"), "exec": exec},
+		},
+		{
+			name:   "host with exec with unknown args",
+			values: map[string]tftypes.Value{"host": str("https://k8s"), "exec": unknownArgExec},
+		},
+		{
+			name:    "exec without host",
+			values:  map[string]tftypes.Value{"exec": exec},
+			wantErr: true,
+		},
+		{
+			name:    "exec with token",
+			values:  map[string]tftypes.Value{"host": str("https://k8s"), "token": str("t"), "exec": exec},
+			wantErr: true,
+		},
+		{
+			name:   "in_cluster only",
+			values: map[string]tftypes.Value{"in_cluster": tftypes.NewValue(tftypes.Bool, true)},
+		},
+		{
+			name:    "in_cluster with kube_config",
+			values:  map[string]tftypes.Value{"in_cluster": tftypes.NewValue(tftypes.Bool, true), "kube_config": str("~/.kube/config")},
+			wantErr: true,
+		},
+		{
+			name:    "host with kube_config",
+			values:  map[string]tftypes.Value{"host": str("https://k8s"), "kube_config": str("~/.kube/config")},
+			wantErr: true,
+		},
+		{
+			name:    "token without host",
+			values:  map[string]tftypes.Value{"token": str("t")},
+			wantErr: true,
+		},
+		{
+			name:    "token with client certificate",
+			values:  map[string]tftypes.Value{"host": str("https://k8s"), "token": str("t"), "client_certificate": str("c"), "client_key": str("k")},
+			wantErr: true,
+		},
+		{
+			name:    "client certificate without key",
+			values:  map[string]tftypes.Value{"host": str("https://k8s"), "client_certificate": str("c")},
+			wantErr: true,
+		},
+		{
+			name:    "insecure with CA",
+			values:  map[string]tftypes.Value{"host": str("https://k8s"), "insecure": tftypes.NewValue(tftypes.Bool, true), "cluster_ca_certificate": str("ca")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := provider.ValidateConfigRequest{Config: testProviderConfig(t, tt.values)}
+			var resp provider.ValidateConfigResponse
+			New().(provider.ProviderWithValidateConfig).ValidateConfig(context.Background(), req, &resp)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("HasError() = %t, want %t: %v", resp.Diagnostics.HasError(), tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestProviderValidateConfigOrder(t *testing.T) {
+	str := func(v string) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+	req := provider.ValidateConfigRequest{Config: testProviderConfig(t, map[string]tftypes.Value{
+		"in_cluster":               tftypes.NewValue(tftypes.Bool, true),
+		"kube_config":              str("~/.kube/config"),
+		"config_context":           str("kind"),
+		"config_context_cluster":   str("kind"),
+		"config_context_auth_info": str("kind"),
+		"host":                     str("https://k8s"),
+	})}
+	want := []string{"config_context", "config_context_auth_info", "config_context_cluster", "host", "kube_config"}
+
+	// Repeat the validation, an order depending on map iteration would
+	// eventually change.
+	for i := 0; i < 20; i++ {
+		var resp provider.ValidateConfigResponse
+		New().(provider.ProviderWithValidateConfig).ValidateConfig(context.Background(), req, &resp)
+		var got []string
+		for _, d := range resp.Diagnostics {
+			got = append(got, d.(diag.DiagnosticWithPath).Path().String())
+		}
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("diagnostics for %v, want %v", got, want)
+		}
+	}
+}
+
+func TestNewClientInClusterOutsideOfPod(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "")
+	if _, err := NewClient(ClientOptions{InCluster: true}); err == nil {
+		t.Error("expected an error when not running inside a pod")
+	}
+}
+
+func TestDeferredClientConnectsOnFirstUse(t *testing.T) {
+	ctx := context.Background()
+	attempts := 0
+	client := newDeferredClient(func(context.Context) (*CiliumClient, error) {
+		attempts++
+		if attempts == 1 {
+			return nil, errors.New("kubeconfig not written yet")
+		}
+		return &CiliumClient{
+			CiliumClientset: ciliumfake.NewSimpleClientset(&ciliumv2.CiliumNode{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}),
+		}, nil
+	})
+
+	_, err := client.ListCiliumNodes(ctx, metav1.ListOptions{})
+	var configErr *clientConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("expected a client configuration error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		nodes, err := client.ListCiliumNodes(ctx, metav1.ListOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(nodes.Items) != 1 {
+			t.Errorf("got %d nodes, want 1", len(nodes.Items))
+		}
+	}
+	if attempts != 2 {
+		t.Errorf("connected %d times, want 2", attempts)
+	}
+}
+
+func TestProviderConfigureDefersUnknownValues(t *testing.T) {
+	ctx := context.Background()
+	req := provider.ConfigureRequest{Config: testProviderConfig(t, map[string]tftypes.Value{
+		"host":  tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+		"token": tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+	})}
+	var resp provider.ConfigureResponse
+	New().Configure(ctx, req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	client, ok := resp.ResourceData.(*CiliumClient)
+	if !ok || resp.DataSourceData != resp.ResourceData {
+		t.Fatalf("expected the same *CiliumClient for resources and data sources, got %T", resp.ResourceData)
+	}
+	_, err := client.ListCiliumNodes(ctx, metav1.ListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "host, token") {
+		t.Errorf("expected an error naming the unknown attributes, got %v", err)
+	}
+}
+
+func TestProviderConfigureDefersUnknownExecArgs(t *testing.T) {
+	ctx := context.Background()
+	str := func(v string) tftypes.Value { return tftypes.NewValue(tftypes.String, v) }
+	listType := tftypes.List{ElementType: tftypes.String}
+	mapType := tftypes.Map{ElementType: tftypes.String}
+	execType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"api_version": tftypes.String,
+		"command":     tftypes.String,
+		"args":        listType,
+		"env":         mapType,
+	}}
+	req := provider.ConfigureRequest{Config: testProviderConfig(t, map[string]tftypes.Value{
+		"host": str("https://k8s"),
+		"exec": tftypes.NewValue(execType, map[string]tftypes.Value{
+			"api_version": str("client.authentication.k8s.io/v1beta1"),
+			"command":     str("aws"),
+			"args":        tftypes.NewValue(listType, []tftypes.Value{str("eks"), str("get-token"), str("--cluster-name"), tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}),
+			"env":         tftypes.NewValue(mapType, nil),
+		}),
+	})}
+	var resp provider.ConfigureResponse
+	New().Configure(ctx, req, &resp)
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+	_, err := resp.ResourceData.(*CiliumClient).ListCiliumNodes(ctx, metav1.ListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "not known yet: exec") {
+		t.Errorf("expected an error naming the exec block, got %v", err)
+	}
+}
+
+func TestProviderConfigureKubeConfigDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+	invalid := filepath.Join(dir, "invalid")
+	if err := os.WriteFile(invalid, []byte("current-context: [\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", missing+string(filepath.ListSeparator)+invalid)
+
+	var resp provider.ConfigureResponse
+	New().Configure(ctx, provider.ConfigureRequest{Config: testProviderConfig(t, nil)}, &resp)
+
+	if resp.Diagnostics.HasError() || resp.Diagnostics.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", resp.Diagnostics)
+	}
+	if warning := resp.Diagnostics.Warnings()[0]; warning.Summary() != "Kubeconfig File Not Found" || !strings.Contains(warning.Detail(), missing) {
+		t.Errorf("unexpected warning %s: %s", warning.Summary(), warning.Detail())
+	}
+
+	_, err := resp.ResourceData.(*CiliumClient).ListCiliumNodes(ctx, metav1.ListOptions{})
+	if err == nil || !strings.Contains(err.Error(), "KUBECONFIG environment variable ("+invalid+")") {
+		t.Errorf("expected an error naming the kubeconfig source, got %v", err)
+	}
+}
+
+func TestListCiliumNodesFollowsContinueToken(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page := ciliumv2.CiliumNodeList{}
+		switch r.URL.Query().Get("continue") {
+		case "":
+			page.Items = []ciliumv2.CiliumNode{{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}}, {ObjectMeta: metav1.ObjectMeta{Name: "node-2"}}}
+			page.Continue = "page-2"
+		case "page-2":
+			page.Items = []ciliumv2.CiliumNode{{ObjectMeta: metav1.ObjectMeta{Name: "node-3"}}}
+			page.ResourceVersion = "42"
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	clientset, err := versioned.NewForConfig(&rest.Config{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &CiliumClient{CiliumClientset: clientset}
+
+	nodes, err := client.ListCiliumNodes(context.Background(), metav1.ListOptions{LabelSelector: "role=worker", Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(nodes.Items) != 3 || nodes.Items[2].Name != "node-3" || nodes.ResourceVersion != "42" {
+		t.Errorf("unexpected nodes %+v", nodes)
+	}
+	want := []string{
+		"labelSelector=role%3Dworker&limit=2",
+		"continue=page-2&labelSelector=role%3Dworker&limit=2",
+	}
+	if strings.Join(queries, " ") != strings.Join(want, " ") {
+		t.Errorf("got queries %q, want %q", queries, want)
+	}
+}
+
+func TestDynamicResourceCachesDiscovery(t *testing.T) {
+	var installed bool
+	var discoveries int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body interface{}
+		switch r.URL.Path {
+		case "/api":
+			body = metav1.APIVersions{}
+		case "/apis":
+			discoveries++
+			groups := metav1.APIGroupList{}
+			if installed {
+				version := metav1.GroupVersionForDiscovery{GroupVersion: ciliumv2.SchemeGroupVersion.String(), Version: "v2"}
+				groups.Groups = []metav1.APIGroup{{Name: ciliumv2.CustomResourceDefinitionGroup, Versions: []metav1.GroupVersionForDiscovery{version}, PreferredVersion: version}}
+			}
+			body = groups
+		case "/apis/cilium.io/v2":
+			body = metav1.APIResourceList{
+				GroupVersion: ciliumv2.SchemeGroupVersion.String(),
+				APIResources: []metav1.APIResource{{Name: ciliumv2.CECPluralName, Kind: ciliumv2.CECKindDefinition, Namespaced: true}},
+			}
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(body)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(ClientOptions{Host: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	gvk := ciliumv2.SchemeGroupVersion.WithKind(ciliumv2.CECKindDefinition)
+
+	// A kind missing from the cache is looked up again before giving up.
+	if _, _, err := client.dynamicResource(ctx, gvk, ""); err == nil {
+		t.Fatal("expected an error for a kind the server does not serve")
+	}
+	if discoveries != 2 {
+		t.Errorf("got %d discoveries, want 2", discoveries)
+	}
+
+	// Once the CRD is installed, the kind is found and then served from the
+	// cache.
+	installed = true
+	for i := 0; i < 3; i++ {
+		if _, namespace, err := client.dynamicResource(ctx, gvk, ""); err != nil || namespace != defaultNamespace {
+			t.Fatalf("unexpected namespace %q or error %v", namespace, err)
+		}
+	}
+	if discoveries != 3 {
+		t.Errorf("got %d discoveries, want 3", discoveries)
+	}
+}